@@ -1,3 +1,4 @@
 package api
 
 //go:generate go run github.com/ogen-go/ogen/cmd/ogen@latest --target ../internal/api --clean config_openapi.yml
+//go:generate protoc --go_out=../internal/grpcapi --go_opt=paths=source_relative --go-grpc_out=../internal/grpcapi --go-grpc_opt=paths=source_relative config.proto
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"time"
@@ -17,6 +19,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Run migrate subcommand instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.Migrate(context.Background(), config, os.Args[2:], os.Stdout); err != nil {
+			if errors.Is(err, app.ErrMigrateUsage) {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			slog.Error("failed to run migrations", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create and initialize application
 	application, err := app.New(context.Background(), config)
 	if err != nil {
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"sw-config-api/internal/cli"
+)
+
+func main() {
+	// Cancel running command on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := cli.Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if errors.Is(err, cli.ErrUsage) {
+			if err != cli.ErrUsage {
+				fmt.Fprintln(os.Stderr, err) // nolint:errcheck
+			}
+			stop()
+			os.Exit(2)
+		}
+		slog.Error("command failed", "error", err)
+		stop()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,10 @@
+// Package migrations embeds SQL schema migrations into the service binaries.
+// MySQL migrations are stored in the package directory, PostgreSQL migrations in the postgres subdirectory.
+package migrations
+
+import "embed"
+
+// FS contains goose SQL migrations for all supported databases
+//
+//go:embed *.sql postgres/*.sql
+var FS embed.FS
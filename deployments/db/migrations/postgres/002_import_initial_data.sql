@@ -0,0 +1,13 @@
+-- +goose Up
+
+-- PostgreSQL has no equivalent of LOAD_FILE for files mounted into the MySQL container.
+-- Initial assets, definitions and their URLs are imported from deployments/db/migrations/data with:
+--   sw-config-cli import --dir deployments/db/migrations/data
+-- The migration is kept to have the same schema versions as the MySQL migrations.
+SELECT 1;
+
+-- +goose Down
+DELETE FROM definition_urls;
+DELETE FROM definitions;
+DELETE FROM asset_urls;
+DELETE FROM assets;
@@ -0,0 +1,14 @@
+-- +goose Up
+
+-- Create platform_versions table
+CREATE TABLE IF NOT EXISTS platform_versions (
+    id SERIAL PRIMARY KEY,
+    platform VARCHAR(50) NOT NULL UNIQUE,
+    required_version VARCHAR(50) NOT NULL,
+    store_version VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS platform_versions;
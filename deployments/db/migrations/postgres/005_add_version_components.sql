@@ -0,0 +1,58 @@
+-- +goose Up
+
+-- Add version component columns to assets table
+ALTER TABLE assets
+ADD COLUMN major INT,
+ADD COLUMN minor INT,
+ADD COLUMN patch INT;
+
+-- Add version component columns to definitions table
+ALTER TABLE definitions
+ADD COLUMN major INT,
+ADD COLUMN minor INT,
+ADD COLUMN patch INT;
+
+-- Update existing data with parsed version components
+UPDATE assets SET
+    major = CAST(SPLIT_PART(version, '.', 1) AS INT),
+    minor = CAST(SPLIT_PART(version, '.', 2) AS INT),
+    patch = CAST(SPLIT_PART(version, '.', 3) AS INT);
+
+UPDATE definitions SET
+    major = CAST(SPLIT_PART(version, '.', 1) AS INT),
+    minor = CAST(SPLIT_PART(version, '.', 2) AS INT),
+    patch = CAST(SPLIT_PART(version, '.', 3) AS INT);
+
+-- Make version components NOT NULL after populating data
+ALTER TABLE assets
+ALTER COLUMN major SET NOT NULL,
+ALTER COLUMN minor SET NOT NULL,
+ALTER COLUMN patch SET NOT NULL;
+
+ALTER TABLE definitions
+ALTER COLUMN major SET NOT NULL,
+ALTER COLUMN minor SET NOT NULL,
+ALTER COLUMN patch SET NOT NULL;
+
+-- Create indexes for efficient version component queries
+CREATE INDEX idx_assets_platform_major_minor_patch ON assets(platform, major, minor, patch);
+
+CREATE INDEX idx_definitions_platform_major_minor_patch ON definitions(platform, major, minor, patch);
+
+-- +goose Down
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_definitions_platform_major_minor_patch;
+
+DROP INDEX IF EXISTS idx_assets_platform_major_minor_patch;
+
+-- Drop version component columns
+ALTER TABLE definitions
+DROP COLUMN patch,
+DROP COLUMN minor,
+DROP COLUMN major;
+
+ALTER TABLE assets
+DROP COLUMN patch,
+DROP COLUMN minor,
+DROP COLUMN major;
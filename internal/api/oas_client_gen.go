@@ -29,7 +29,8 @@ func trimTrailingSlashes(u *url.URL) {
 type Invoker interface {
 	// ConfigGet invokes GET /config operation.
 	//
-	// Get configuration for client.
+	// Every response carries the X-Request-ID header. A valid ID sent by the caller
+	// (up to 128 letters, digits and -_.: characters) is echoed back, otherwise a new one is generated.
 	//
 	// GET /config
 	ConfigGet(ctx context.Context, params ConfigGetParams) (ConfigGetRes, error)
@@ -80,7 +81,8 @@ func (c *Client) requestURL(ctx context.Context) *url.URL {
 
 // ConfigGet invokes GET /config operation.
 //
-// Get configuration for client.
+// Every response carries the X-Request-ID header. A valid ID sent by the caller
+// (up to 128 letters, digits and -_.: characters) is echoed back, otherwise a new one is generated.
 //
 // GET /config
 func (c *Client) ConfigGet(ctx context.Context, params ConfigGetParams) (ConfigGetRes, error) {
@@ -31,7 +31,8 @@ func (c *codeRecorder) WriteHeader(status int) {
 
 // handleConfigGetRequest handles GET /config operation.
 //
-// Get configuration for client.
+// Every response carries the X-Request-ID header. A valid ID sent by the caller
+// (up to 128 letters, digits and -_.: characters) is echoed back, otherwise a new one is generated.
 //
 // GET /config
 func (s *Server) handleConfigGetRequest(args [0]string, argsEscaped bool, w http.ResponseWriter, r *http.Request) {
@@ -76,8 +76,6 @@ func (s *Config) SetNotifications(val OptBackendService) {
 	s.Notifications = val
 }
 
-func (*Config) configGetRes() {}
-
 type ConfigGetBadRequest struct {
 	Error OptConfigGetBadRequestError `json:"error"`
 }
@@ -97,6 +95,8 @@ func (*ConfigGetBadRequest) configGetRes() {}
 type ConfigGetBadRequestError struct {
 	Code    OptInt    `json:"code"`
 	Message OptString `json:"message"`
+	// ID of the request, also returned in the X-Request-ID header.
+	RequestID OptString `json:"request_id"`
 }
 
 // GetCode returns the value of Code.
@@ -109,6 +109,11 @@ func (s *ConfigGetBadRequestError) GetMessage() OptString {
 	return s.Message
 }
 
+// GetRequestID returns the value of RequestID.
+func (s *ConfigGetBadRequestError) GetRequestID() OptString {
+	return s.RequestID
+}
+
 // SetCode sets the value of Code.
 func (s *ConfigGetBadRequestError) SetCode(val OptInt) {
 	s.Code = val
@@ -119,6 +124,11 @@ func (s *ConfigGetBadRequestError) SetMessage(val OptString) {
 	s.Message = val
 }
 
+// SetRequestID sets the value of RequestID.
+func (s *ConfigGetBadRequestError) SetRequestID(val OptString) {
+	s.RequestID = val
+}
+
 type ConfigGetNotFound struct {
 	Error OptConfigGetNotFoundError `json:"error"`
 }
@@ -138,6 +148,8 @@ func (*ConfigGetNotFound) configGetRes() {}
 type ConfigGetNotFoundError struct {
 	Code    OptInt    `json:"code"`
 	Message OptString `json:"message"`
+	// ID of the request, also returned in the X-Request-ID header.
+	RequestID OptString `json:"request_id"`
 }
 
 // GetCode returns the value of Code.
@@ -150,6 +162,11 @@ func (s *ConfigGetNotFoundError) GetMessage() OptString {
 	return s.Message
 }
 
+// GetRequestID returns the value of RequestID.
+func (s *ConfigGetNotFoundError) GetRequestID() OptString {
+	return s.RequestID
+}
+
 // SetCode sets the value of Code.
 func (s *ConfigGetNotFoundError) SetCode(val OptInt) {
 	s.Code = val
@@ -160,6 +177,178 @@ func (s *ConfigGetNotFoundError) SetMessage(val OptString) {
 	s.Message = val
 }
 
+// SetRequestID sets the value of RequestID.
+func (s *ConfigGetNotFoundError) SetRequestID(val OptString) {
+	s.RequestID = val
+}
+
+type ConfigGetServiceUnavailable struct {
+	Error OptConfigGetServiceUnavailableError `json:"error"`
+}
+
+// GetError returns the value of Error.
+func (s *ConfigGetServiceUnavailable) GetError() OptConfigGetServiceUnavailableError {
+	return s.Error
+}
+
+// SetError sets the value of Error.
+func (s *ConfigGetServiceUnavailable) SetError(val OptConfigGetServiceUnavailableError) {
+	s.Error = val
+}
+
+func (*ConfigGetServiceUnavailable) configGetRes() {}
+
+type ConfigGetServiceUnavailableError struct {
+	Code    OptInt    `json:"code"`
+	Message OptString `json:"message"`
+	// ID of the request, also returned in the X-Request-ID header.
+	RequestID OptString `json:"request_id"`
+}
+
+// GetCode returns the value of Code.
+func (s *ConfigGetServiceUnavailableError) GetCode() OptInt {
+	return s.Code
+}
+
+// GetMessage returns the value of Message.
+func (s *ConfigGetServiceUnavailableError) GetMessage() OptString {
+	return s.Message
+}
+
+// GetRequestID returns the value of RequestID.
+func (s *ConfigGetServiceUnavailableError) GetRequestID() OptString {
+	return s.RequestID
+}
+
+// SetCode sets the value of Code.
+func (s *ConfigGetServiceUnavailableError) SetCode(val OptInt) {
+	s.Code = val
+}
+
+// SetMessage sets the value of Message.
+func (s *ConfigGetServiceUnavailableError) SetMessage(val OptString) {
+	s.Message = val
+}
+
+// SetRequestID sets the value of RequestID.
+func (s *ConfigGetServiceUnavailableError) SetRequestID(val OptString) {
+	s.RequestID = val
+}
+
+type ConfigGetTooManyRequests struct {
+	Error OptConfigGetTooManyRequestsError `json:"error"`
+}
+
+// GetError returns the value of Error.
+func (s *ConfigGetTooManyRequests) GetError() OptConfigGetTooManyRequestsError {
+	return s.Error
+}
+
+// SetError sets the value of Error.
+func (s *ConfigGetTooManyRequests) SetError(val OptConfigGetTooManyRequestsError) {
+	s.Error = val
+}
+
+func (*ConfigGetTooManyRequests) configGetRes() {}
+
+type ConfigGetTooManyRequestsError struct {
+	Code    OptInt    `json:"code"`
+	Message OptString `json:"message"`
+	// ID of the request, also returned in the X-Request-ID header.
+	RequestID OptString `json:"request_id"`
+}
+
+// GetCode returns the value of Code.
+func (s *ConfigGetTooManyRequestsError) GetCode() OptInt {
+	return s.Code
+}
+
+// GetMessage returns the value of Message.
+func (s *ConfigGetTooManyRequestsError) GetMessage() OptString {
+	return s.Message
+}
+
+// GetRequestID returns the value of RequestID.
+func (s *ConfigGetTooManyRequestsError) GetRequestID() OptString {
+	return s.RequestID
+}
+
+// SetCode sets the value of Code.
+func (s *ConfigGetTooManyRequestsError) SetCode(val OptInt) {
+	s.Code = val
+}
+
+// SetMessage sets the value of Message.
+func (s *ConfigGetTooManyRequestsError) SetMessage(val OptString) {
+	s.Message = val
+}
+
+// SetRequestID sets the value of RequestID.
+func (s *ConfigGetTooManyRequestsError) SetRequestID(val OptString) {
+	s.RequestID = val
+}
+
+// ConfigHeaders wraps Config with response headers.
+type ConfigHeaders struct {
+	CacheControl OptString
+	SurrogateKey OptString
+	Vary         OptString
+	XConfigStale OptBool
+	Response     Config
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *ConfigHeaders) GetCacheControl() OptString {
+	return s.CacheControl
+}
+
+// GetSurrogateKey returns the value of SurrogateKey.
+func (s *ConfigHeaders) GetSurrogateKey() OptString {
+	return s.SurrogateKey
+}
+
+// GetVary returns the value of Vary.
+func (s *ConfigHeaders) GetVary() OptString {
+	return s.Vary
+}
+
+// GetXConfigStale returns the value of XConfigStale.
+func (s *ConfigHeaders) GetXConfigStale() OptBool {
+	return s.XConfigStale
+}
+
+// GetResponse returns the value of Response.
+func (s *ConfigHeaders) GetResponse() Config {
+	return s.Response
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *ConfigHeaders) SetCacheControl(val OptString) {
+	s.CacheControl = val
+}
+
+// SetSurrogateKey sets the value of SurrogateKey.
+func (s *ConfigHeaders) SetSurrogateKey(val OptString) {
+	s.SurrogateKey = val
+}
+
+// SetVary sets the value of Vary.
+func (s *ConfigHeaders) SetVary(val OptString) {
+	s.Vary = val
+}
+
+// SetXConfigStale sets the value of XConfigStale.
+func (s *ConfigHeaders) SetXConfigStale(val OptBool) {
+	s.XConfigStale = val
+}
+
+// SetResponse sets the value of Response.
+func (s *ConfigHeaders) SetResponse(val Config) {
+	s.Response = val
+}
+
+func (*ConfigHeaders) configGetRes() {}
+
 // NewOptBackendService returns new OptBackendService with value set to v.
 func NewOptBackendService(v BackendService) OptBackendService {
 	return OptBackendService{
@@ -206,6 +395,52 @@ func (o OptBackendService) Or(d BackendService) BackendService {
 	return d
 }
 
+// NewOptBool returns new OptBool with value set to v.
+func NewOptBool(v bool) OptBool {
+	return OptBool{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptBool is optional bool.
+type OptBool struct {
+	Value bool
+	Set   bool
+}
+
+// IsSet returns true if OptBool was set.
+func (o OptBool) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptBool) Reset() {
+	var v bool
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptBool) SetTo(v bool) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptBool) Get() (v bool, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptBool) Or(d bool) bool {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptConfigGetBadRequestError returns new OptConfigGetBadRequestError with value set to v.
 func NewOptConfigGetBadRequestError(v ConfigGetBadRequestError) OptConfigGetBadRequestError {
 	return OptConfigGetBadRequestError{
@@ -298,6 +533,98 @@ func (o OptConfigGetNotFoundError) Or(d ConfigGetNotFoundError) ConfigGetNotFoun
 	return d
 }
 
+// NewOptConfigGetServiceUnavailableError returns new OptConfigGetServiceUnavailableError with value set to v.
+func NewOptConfigGetServiceUnavailableError(v ConfigGetServiceUnavailableError) OptConfigGetServiceUnavailableError {
+	return OptConfigGetServiceUnavailableError{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptConfigGetServiceUnavailableError is optional ConfigGetServiceUnavailableError.
+type OptConfigGetServiceUnavailableError struct {
+	Value ConfigGetServiceUnavailableError
+	Set   bool
+}
+
+// IsSet returns true if OptConfigGetServiceUnavailableError was set.
+func (o OptConfigGetServiceUnavailableError) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptConfigGetServiceUnavailableError) Reset() {
+	var v ConfigGetServiceUnavailableError
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptConfigGetServiceUnavailableError) SetTo(v ConfigGetServiceUnavailableError) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptConfigGetServiceUnavailableError) Get() (v ConfigGetServiceUnavailableError, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptConfigGetServiceUnavailableError) Or(d ConfigGetServiceUnavailableError) ConfigGetServiceUnavailableError {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptConfigGetTooManyRequestsError returns new OptConfigGetTooManyRequestsError with value set to v.
+func NewOptConfigGetTooManyRequestsError(v ConfigGetTooManyRequestsError) OptConfigGetTooManyRequestsError {
+	return OptConfigGetTooManyRequestsError{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptConfigGetTooManyRequestsError is optional ConfigGetTooManyRequestsError.
+type OptConfigGetTooManyRequestsError struct {
+	Value ConfigGetTooManyRequestsError
+	Set   bool
+}
+
+// IsSet returns true if OptConfigGetTooManyRequestsError was set.
+func (o OptConfigGetTooManyRequestsError) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptConfigGetTooManyRequestsError) Reset() {
+	var v ConfigGetTooManyRequestsError
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptConfigGetTooManyRequestsError) SetTo(v ConfigGetTooManyRequestsError) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptConfigGetTooManyRequestsError) Get() (v ConfigGetTooManyRequestsError, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptConfigGetTooManyRequestsError) Or(d ConfigGetTooManyRequestsError) ConfigGetTooManyRequestsError {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptInt returns new OptInt with value set to v.
 func NewOptInt(v int) OptInt {
 	return OptInt{
@@ -10,7 +10,8 @@ import (
 type Handler interface {
 	// ConfigGet implements GET /config operation.
 	//
-	// Get configuration for client.
+	// Every response carries the X-Request-ID header. A valid ID sent by the caller
+	// (up to 128 letters, digits and -_.: characters) is echoed back, otherwise a new one is generated.
 	//
 	// GET /config
 	ConfigGet(ctx context.Context, params ConfigGetParams) (ConfigGetRes, error)
@@ -15,7 +15,8 @@ var _ Handler = UnimplementedHandler{}
 
 // ConfigGet implements GET /config operation.
 //
-// Get configuration for client.
+// Every response carries the X-Request-ID header. A valid ID sent by the caller
+// (up to 128 letters, digits and -_.: characters) is echoed back, otherwise a new one is generated.
 //
 // GET /config
 func (UnimplementedHandler) ConfigGet(ctx context.Context, params ConfigGetParams) (r ConfigGetRes, _ error) {
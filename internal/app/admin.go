@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"sw-config-api/internal/logging"
+)
+
+// Version is the release of the service, set at build time with
+// -ldflags "-X sw-config-api/internal/app.Version=1.2.3"
+var Version = "dev"
+
+// redacted replaces values of configuration fields tagged secret:"true"
+const redacted = "[REDACTED]"
+
+// adminRoutes serves operational endpoints on the admin listener.
+// They expose internals of the service and must not be reachable by clients.
+func (app *Application) adminRoutes() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", app.metricsHandler)
+	mux.Handle("/log-level", logging.LevelHandler(app.logs.Level, app.logger))
+	mux.HandleFunc("GET /buildinfo", app.handleBuildInfo)
+	mux.HandleFunc("GET /config", app.handleConfig)
+	mux.HandleFunc("GET /diagnostics", app.handleDiagnostics)
+
+	// Profiles of the running process
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// buildInfo describes the running binary
+type buildInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	Modified   bool   `json:"modified,omitempty"` // built from a tree with uncommitted changes
+	GoVersion  string `json:"go_version"`
+}
+
+// readBuildInfo returns the version set at build time and VCS information embedded by the Go toolchain
+func readBuildInfo() buildInfo {
+	info := buildInfo{Version: Version, GoVersion: runtime.Version()}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Commit = setting.Value
+		case "vcs.time":
+			info.CommitTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+func (app *Application) handleBuildInfo(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, readBuildInfo())
+}
+
+// handleConfig returns the effective configuration by environment variable
+func (app *Application) handleConfig(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, effectiveConfig(app.config))
+}
+
+// effectiveConfig maps environment variables to their values.
+// Values of fields tagged secret:"true" are redacted unless they are empty.
+func effectiveConfig(config *Config) map[string]any {
+	values := make(map[string]any)
+
+	value := reflect.ValueOf(config).Elem()
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+		if name == "" {
+			continue
+		}
+
+		fieldValue := value.Field(i).Interface()
+		if field.Tag.Get("secret") == "true" && !value.Field(i).IsZero() {
+			fieldValue = redacted
+		}
+		values[name] = fieldValue
+	}
+	return values
+}
+
+// databaseDiagnostics describes the connection pool of the database
+type databaseDiagnostics struct {
+	Driver          string  `json:"driver"`
+	MaxOpen         int     `json:"max_open"`
+	Open            int     `json:"open"`
+	InUse           int     `json:"in_use"`
+	Idle            int     `json:"idle"`
+	WaitCount       int64   `json:"wait_count"`
+	WaitDurationSec float64 `json:"wait_duration_seconds"`
+	PingError       string  `json:"ping_error,omitempty"`
+}
+
+// snapshotDiagnostics describes the in-memory snapshot configuration is resolved from
+type snapshotDiagnostics struct {
+	CreatedAt time.Time `json:"created_at"`
+	AgeSec    float64   `json:"age_seconds"`
+}
+
+// cacheDiagnostics describes Redis and the in-process cache
+type cacheDiagnostics struct {
+	Addr                string `json:"addr"`
+	Healthy             bool   `json:"healthy"` // calls are allowed by the circuit breaker
+	PingError           string `json:"ping_error,omitempty"`
+	LocalEntries        *int   `json:"local_entries,omitempty"` // nil when the local cache is disabled
+	InvalidationEnabled bool   `json:"invalidation_enabled"`
+	WarmupEnabled       bool   `json:"warmup_enabled"`
+}
+
+type diagnosticsResponse struct {
+	Database *databaseDiagnostics `json:"database,omitempty"` // nil when storage is not backed by a database
+	Snapshot *snapshotDiagnostics `json:"snapshot,omitempty"` // nil when data is resolved by database queries
+	Cache    cacheDiagnostics     `json:"cache"`
+}
+
+// handleDiagnostics reports the state of the database, the snapshot and the caches
+func (app *Application) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	var response diagnosticsResponse
+	if app.db != nil {
+		stats := app.db.Stats()
+		response.Database = &databaseDiagnostics{
+			Driver:          app.config.StorageDriver,
+			MaxOpen:         stats.MaxOpenConnections,
+			Open:            stats.OpenConnections,
+			InUse:           stats.InUse,
+			Idle:            stats.Idle,
+			WaitCount:       stats.WaitCount,
+			WaitDurationSec: stats.WaitDuration.Seconds(),
+		}
+		if err := app.db.PingContext(ctx); err != nil {
+			response.Database.PingError = err.Error()
+		}
+	}
+
+	if app.snapshots != nil {
+		createdAt := app.snapshots.Load().CreatedAt()
+		response.Snapshot = &snapshotDiagnostics{
+			CreatedAt: createdAt,
+			AgeSec:    time.Since(createdAt).Seconds(),
+		}
+	}
+
+	response.Cache = cacheDiagnostics{
+		Addr:                app.config.RedisAddr,
+		Healthy:             app.redisCache.Healthy(),
+		InvalidationEnabled: app.invalidationEnabled,
+		WarmupEnabled:       app.warmer != nil,
+	}
+	if err := app.redisCache.Ping(ctx); err != nil {
+		response.Cache.PingError = err.Error()
+	}
+	if app.localCache != nil {
+		entries := app.localCache.Len()
+		response.Cache.LocalEntries = &entries
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sw-config-api/internal/logging"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	config := &Config{
+		DBHost:     "db",
+		DBPassword: "rootpassword",
+		RedisAddr:  "redis:6379",
+		CacheTTL:   300,
+	}
+
+	values := effectiveConfig(config)
+	assert.Equal(t, "db", values["DB_HOST"])
+	assert.Equal(t, redacted, values["DB_PASSWORD"])
+	assert.Equal(t, "", values["REDIS_PASSWORD"]) // Empty secrets show that none is configured
+	assert.Equal(t, 300, values["CACHE_TTL_SECONDS"])
+	assert.NotContains(t, values, "DBPassword")
+}
+
+func TestAdminRoutes(t *testing.T) {
+	logs, err := logging.New(logging.Config{Level: "info", Format: logging.FormatJSON})
+	require.NoError(t, err)
+
+	app := &Application{
+		logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		logs:           logs,
+		config:         &Config{DBPassword: "rootpassword"},
+		metricsHandler: http.NotFoundHandler(),
+	}
+	handler := app.adminRoutes()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	recorder := get("/buildinfo")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var info buildInfo
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&info))
+	assert.Equal(t, Version, info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+
+	recorder = get("/config")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "rootpassword")
+
+	assert.Equal(t, http.StatusOK, get("/log-level").Code)
+	assert.Equal(t, http.StatusOK, get("/debug/pprof/").Code)
+}
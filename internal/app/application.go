@@ -3,169 +3,471 @@ package app
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"sw-config-api/internal/api"
 	"sw-config-api/internal/cache"
+	"sw-config-api/internal/logging"
 	"sw-config-api/internal/middleware"
+	"sw-config-api/internal/requestid"
 	"sw-config-api/internal/service"
 	"sw-config-api/internal/storage"
 
 	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type Application struct {
-	logger     *slog.Logger
-	db         *sqlx.DB
-	cache      cache.Interface
-	handler    *service.Handler
-	apiServer  *api.Server
-	httpServer *http.Server
-}
+	logger        *slog.Logger
+	logs          *logging.Logger // owns the log output and the runtime log level
+	db            *sqlx.DB
+	storage       io.Closer
+	refresher     snapshotRefresher
+	statements    []storage.StatementChecker
+	cache         cache.Interface
+	redisCache    *cache.RedisCache
+	configService *service.CachedConfigService
+	handler       *service.Handler
+	apiServer     *api.Server
+	apiHandler    http.Handler // apiServer behind rate limiting and load shedding
+	httpServer    *http.Server
+	adminServer   *http.Server  // nil when ADMIN_ADDR is empty
+	certReloader  *certReloader // nil when TLS is not terminated by the service
+	grpcServer    *grpc.Server  // nil when GRPC_ADDR is empty
+	grpcHealth    *health.Server
 
-func New(ctx context.Context, config *Config) (*Application, error) {
-	// Initialize structured logger
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-	slog.SetDefault(logger)
+	tlsReloadInterval time.Duration
+	config            *Config
+	localCache        *cache.MemoryCache     // nil when the local cache is disabled
+	snapshots         *storage.SnapshotStore // nil when data is resolved by database queries
 
-	// Initialize storage
-	storageConfig := &storage.Config{
-		Host:     config.DBHost,
-		Port:     config.DBPort,
-		User:     config.DBUser,
-		Password: config.DBPassword,
-		DBName:   config.DBName,
-	}
+	meterProvider  *sdkmetric.MeterProvider
+	metricsHandler http.Handler
+	tracerProvider *sdktrace.TracerProvider // nil when spans are not exported
 
-	db, err := storage.New(storageConfig)
-	if err != nil {
-		return nil, err
-	}
+	invalidationEnabled bool
+	retryInterval       time.Duration
+	warmer              *service.Warmer // nil when warm-up is disabled
+	warmupBlocking      bool
+	warmupTimeout       time.Duration
+	cancelBackground    context.CancelFunc
+	background          sync.WaitGroup
 
-	// Initialize Redis cache
-	redisCache, err := cache.NewRedisCache(
-		config.RedisAddr,
-		config.RedisPassword,
-		config.RedisDB,
-	)
+	ready         atomic.Bool // Reported by /readyz, set after startup and cleared on shutdown
+	shutdownDelay time.Duration
+}
+
+func New(ctx context.Context, config *Config) (*Application, error) {
+	// Initialize structured logger, its level can be changed at runtime
+	logs, err := logging.New(config.LoggingConfig())
 	if err != nil {
 		return nil, err
 	}
+	logger := logs.Logger
+	slog.SetDefault(logger)
 
-	// Initialize repositories
-	assetRepository, err := storage.NewResourceRepository(ctx, db, "assets", storage.MajorOnly)
+	// Export metrics of all components to Prometheus
+	meterProvider, metricsHandler, err := newMetrics()
 	if err != nil {
 		return nil, err
 	}
 
-	definitionRepository, err := storage.NewResourceRepository(ctx, db, "definitions", storage.MajorMinor)
+	// Export spans and continue traces of callers
+	tracerProvider, err := newTracing(ctx, config)
 	if err != nil {
 		return nil, err
 	}
 
-	assetURLRepository, err := storage.NewURLRepository(ctx, db, "asset_urls")
+	// Initialize repositories
+	repos, err := newRepositories(ctx, config, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	definitionURLRepository, err := storage.NewURLRepository(ctx, db, "definition_urls")
-	if err != nil {
-		return nil, err
+	// Report connection pool statistics and query latency when the database is used
+	if repos.db != nil {
+		if _, err := storage.RegisterDatabaseMetrics(otel.Meter("sw-config-api/storage"), repos.db); err != nil {
+			return nil, err
+		}
+	}
+	if repos.queried {
+		instrumentation, err := service.NewRepositoryInstrumentation(otel.Meter("sw-config-api/storage"))
+		if err != nil {
+			return nil, err
+		}
+		repos.instrument(instrumentation)
 	}
 
-	platformVersionRepository, err := storage.NewPlatformVersionRepository(ctx, db)
-	if err != nil {
-		return nil, err
+	// Report snapshot age when configuration is resolved in memory
+	if repos.snapshots != nil {
+		if _, err := storage.RegisterSnapshotMetrics(otel.Meter("sw-config-api/storage"), repos.snapshots); err != nil {
+			return nil, err
+		}
 	}
 
-	entryPointRepository, err := storage.NewEntryPointRepository(db)
-	if err != nil {
-		return nil, err
+	// Initialize Redis cache, the service starts and keeps working without Redis
+	redisCache := cache.NewResilientRedisCache(cache.RedisOptions{
+		Addr:             config.RedisAddr,
+		Password:         config.RedisPassword,
+		DB:               config.RedisDB,
+		ReadTimeout:      time.Duration(config.RedisReadTimeout) * time.Millisecond,
+		WriteTimeout:     time.Duration(config.RedisWriteTimeout) * time.Millisecond,
+		FailureThreshold: config.RedisFailureThreshold,
+		CheckInterval:    time.Duration(config.RedisCheckInterval) * time.Second,
+	}, logger)
+
+	// Optionally put an in-process cache in front of Redis
+	var (
+		configCache cache.Interface = redisCache
+		localCache  *cache.MemoryCache
+	)
+	if config.LocalCacheEnabled {
+		localCache = cache.NewMemoryCache(config.LocalCacheMaxEntries)
+		configCache, err = cache.NewTieredCache(
+			localCache,
+			redisCache,
+			time.Duration(config.LocalCacheTTL)*time.Second,
+			otel.Meter("sw-config-api/cache"),
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Initialize config service
 	configService := service.NewConfigService(
-		assetRepository,
-		definitionRepository,
-		assetURLRepository,
-		definitionURLRepository,
-		platformVersionRepository,
-		entryPointRepository,
+		repos.assets,
+		repos.definitions,
+		repos.assetURLs,
+		repos.definitionURLs,
+		repos.platformVersions,
+		repos.entryPoints,
 	)
 
 	// Wrap with caching
 	cachedConfigService := service.NewCachedConfigService(
 		configService,
-		redisCache,
+		configCache,
 		time.Duration(config.CacheTTL)*time.Second,
 		logger,
 	)
+	cachedConfigService.EnableServeStale(time.Duration(config.CacheStaleTTL) * time.Second)
+	if err := cachedConfigService.EnableMetrics(otel.Meter("sw-config-api/service")); err != nil {
+		return nil, err
+	}
+	if config.CacheLockEnabled {
+		cachedConfigService.EnableLocking(
+			redisCache,
+			time.Duration(config.CacheLockTTL)*time.Millisecond,
+			time.Duration(config.CacheLockWait)*time.Millisecond,
+		)
+	}
+
+	var warmer *service.Warmer
+	if config.WarmupEnabled {
+		warmer = service.NewWarmer(cachedConfigService, repos.versionLines, config.WarmupConcurrency, logger)
+	}
 
 	// Initialize handler with cached config service
 	handler := service.NewHandler(cachedConfigService, logger)
+	if config.HTTPCacheEnabled {
+		handler.EnableCacheHeaders(config.HTTPCacheControl, config.HTTPCacheControlStale, config.HTTPVary)
+	}
+	if err := handler.EnableMetrics(otel.Meter("sw-config-api/service")); err != nil {
+		return nil, err
+	}
+
+	metricsMiddleware, err := middleware.MetricsMiddleware(otel.Meter("sw-config-api/http"))
+	if err != nil {
+		return nil, err
+	}
 
-	// Create API server with custom error handler and logging middleware
+	// Create API server with custom error handler, logging and metrics middleware
 	apiServer, err := api.NewServer(
 		handler,
 		api.WithErrorHandler(func(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
 			middleware.CustomErrorHandler(ctx, w, r, err, logger)
 		}),
 		api.WithMiddleware(
-			middleware.LoggingMiddleware(logger),
+			middleware.LoggingMiddleware(logger, config.LogRequestSamplePercent),
+			metricsMiddleware,
 		),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create HTTP server wrapper for graceful shutdown
-	httpServer := &http.Server{
-		Addr:         config.ServerAddr,
-		Handler:      apiServer,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	// Create HTTP server wrapper for graceful shutdown, routes are set up below
+	// Reject requests over the limits before they reach the API
+	var apiHandler http.Handler = middleware.TracePropagation(apiServer)
+	if config.CompressionEnabled {
+		apiHandler = middleware.Compress(config.CompressionMinSize)(apiHandler)
 	}
+	apiHandler, err = limitRequests(config, apiHandler, redisCache, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	httpServer, certReloader, err := newHTTPServer(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serve the same configurations over gRPC for backend services
+	var (
+		grpcServer *grpc.Server
+		grpcHealth *health.Server
+	)
+	if config.GRPCAddr != "" {
+		grpcHandler := service.NewGRPCHandler(cachedConfigService, logger)
+		if err := grpcHandler.EnableMetrics(otel.Meter("sw-config-api/service")); err != nil {
+			return nil, err
+		}
+
+		// Reported as serving between startup and shutdown, as /readyz
+		grpcHealth = health.NewServer()
+		grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+		grpcServer, err = newGRPCServer(config, httpServer.TLSConfig, grpcHandler, grpcHealth, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	app := &Application{
+		logger:              logger,
+		logs:                logs,
+		db:                  repos.db,
+		storage:             repos.closer,
+		refresher:           repos.refresher,
+		statements:          repos.statements,
+		cache:               configCache,
+		redisCache:          redisCache,
+		configService:       cachedConfigService,
+		handler:             handler,
+		apiServer:           apiServer,
+		apiHandler:          apiHandler,
+		httpServer:          httpServer,
+		certReloader:        certReloader,
+		grpcServer:          grpcServer,
+		grpcHealth:          grpcHealth,
+		tlsReloadInterval:   time.Duration(config.TLSReloadInterval) * time.Second,
+		config:              config,
+		localCache:          localCache,
+		snapshots:           repos.snapshots,
+		meterProvider:       meterProvider,
+		metricsHandler:      metricsHandler,
+		tracerProvider:      tracerProvider,
+		invalidationEnabled: config.CacheInvalidationEnabled,
+		retryInterval:       time.Duration(config.RedisCheckInterval) * time.Second,
+		warmer:              warmer,
+		warmupBlocking:      config.WarmupBlocking,
+		warmupTimeout:       time.Duration(config.WarmupTimeout) * time.Second,
+		shutdownDelay:       time.Duration(config.ShutdownDelay) * time.Second,
+	}
+	httpServer.Handler = app.routes()
+
+	// Drop configurations cached from data that has changed on reload
+	if repos.snapshots != nil {
+		repos.snapshots.OnChange(app.invalidateChanges)
+	}
+
+	// Operational endpoints are served on a separate listener that is not exposed to clients
+	if config.AdminAddr != "" {
+		app.adminServer = &http.Server{
+			Addr:        config.AdminAddr,
+			Handler:     app.adminRoutes(),
+			ReadTimeout: 15 * time.Second,
+			IdleTimeout: 60 * time.Second,
+			// No write timeout: CPU profiles and execution traces are written for their whole duration
+		}
+	}
+
+	// Report storage and cache availability separately
+	if err := app.registerHealthMetrics(otel.Meter("sw-config-api/health")); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// RefreshSnapshot reloads the in-memory snapshot from the database.
+// It does nothing when snapshot mode is disabled.
+func (app *Application) RefreshSnapshot(ctx context.Context) error {
+	if app.refresher == nil {
+		return nil
+	}
+	return app.refresher.Refresh(ctx)
+}
 
-	return &Application{
-		logger:     logger,
-		db:         db,
-		cache:      redisCache,
-		handler:    handler,
-		apiServer:  apiServer,
-		httpServer: httpServer,
-	}, nil
+// routes serves health checks next to the API. Operational endpoints are served by adminRoutes.
+func (app *Application) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", app.handleLiveness)
+	mux.HandleFunc("GET /readyz", app.handleReadiness)
+	mux.Handle("/", requestid.Middleware(app.apiHandler))
+	return mux
 }
 
 func (app *Application) Start() error {
+	// Every listener is bound before anything starts, so that a taken port fails Start
+	// without leaving other servers running
+	apiListener, grpcListener, adminListener, err := app.listen()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	app.cancelBackground = cancel
+
+	if app.invalidationEnabled {
+		app.background.Add(1)
+		go app.subscribeInvalidations(ctx)
+	}
+
+	if app.certReloader != nil && app.tlsReloadInterval > 0 {
+		app.background.Add(1)
+		go func() {
+			defer app.background.Done()
+			app.certReloader.watch(ctx, app.tlsReloadInterval)
+		}()
+	}
+
 	go func() {
-		app.logger.Info("starting HTTP server", "addr", app.httpServer.Addr)
-		if err := app.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		app.logger.Info("starting HTTP server", "addr", app.httpServer.Addr, "tls", app.certReloader != nil)
+		serve := app.httpServer.Serve
+		if app.certReloader != nil {
+			// The certificate is served by TLSConfig.GetCertificate
+			serve = func(listener net.Listener) error { return app.httpServer.ServeTLS(listener, "", "") }
+		}
+		if err := serve(apiListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			app.logger.Error("server error", "error", err)
 			os.Exit(1)
 		}
 	}()
 
+	if app.grpcServer != nil {
+		go func() {
+			app.logger.Info("starting gRPC server", "addr", app.config.GRPCAddr, "tls", app.certReloader != nil)
+			if err := app.grpcServer.Serve(grpcListener); err != nil {
+				app.logger.Error("gRPC server error", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	if app.adminServer != nil {
+		go func() {
+			app.logger.Info("starting admin server", "addr", app.adminServer.Addr)
+			if err := app.adminServer.Serve(adminListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				app.logger.Error("admin server error", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	// Listeners are started before warm-up, so that health checks answer while it runs.
+	// Readiness is reported only after a blocking warm-up.
+	if app.warmer != nil {
+		if app.warmupBlocking {
+			app.warmup(ctx, nil)
+		} else {
+			app.warmupInBackground(ctx, nil)
+		}
+	}
+	app.ready.Store(true)
+	if app.grpcHealth != nil {
+		app.grpcHealth.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	}
+
 	return nil
 }
 
+// listen binds the API listener and the gRPC and admin ones when they are enabled.
+// On failure the listeners bound so far are closed.
+func (app *Application) listen() (apiListener, grpcListener, adminListener net.Listener, err error) {
+	defer func() {
+		if err != nil {
+			for _, listener := range []net.Listener{apiListener, grpcListener, adminListener} {
+				if listener != nil {
+					_ = listener.Close()
+				}
+			}
+		}
+	}()
+
+	if apiListener, err = net.Listen("tcp", app.httpServer.Addr); err != nil {
+		return apiListener, grpcListener, adminListener, err
+	}
+	if app.grpcServer != nil {
+		if grpcListener, err = net.Listen("tcp", app.config.GRPCAddr); err != nil {
+			return apiListener, grpcListener, adminListener, err
+		}
+	}
+	if app.adminServer != nil {
+		if adminListener, err = net.Listen("tcp", app.adminServer.Addr); err != nil {
+			return apiListener, grpcListener, adminListener, err
+		}
+	}
+	return apiListener, grpcListener, adminListener, nil
+}
+
 func (app *Application) Shutdown(ctx context.Context) error {
 	app.logger.Info("shutting down server...")
 
+	// Fail readiness first so that the load balancer stops sending traffic before draining
+	app.ready.Store(false)
+	if app.grpcHealth != nil {
+		app.grpcHealth.Shutdown()
+	}
+	if app.shutdownDelay > 0 {
+		select {
+		case <-time.After(app.shutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
 	if err := app.httpServer.Shutdown(ctx); err != nil {
 		app.logger.Error("server forced to shutdown", "error", err)
 		return err
 	}
 
-	if err := app.db.Close(); err != nil {
-		app.logger.Error("failed to close database", "error", err)
+	if app.grpcServer != nil {
+		if err := app.shutdownGRPC(ctx); err != nil {
+			app.logger.Error("gRPC server forced to shutdown", "error", err)
+			return err
+		}
+	}
+
+	// The admin server stops after the API, so that metrics of draining are still scraped
+	if app.adminServer != nil {
+		if err := app.adminServer.Shutdown(ctx); err != nil {
+			app.logger.Error("admin server forced to shutdown", "error", err)
+			return err
+		}
+	}
+
+	if app.cancelBackground != nil {
+		app.cancelBackground()
+		app.background.Wait()
+	}
+
+	if err := app.storage.Close(); err != nil {
+		app.logger.Error("failed to close storage", "error", err)
 		return err
 	}
 
@@ -174,7 +476,139 @@ func (app *Application) Shutdown(ctx context.Context) error {
 		return err
 	}
 
+	if err := app.meterProvider.Shutdown(ctx); err != nil {
+		app.logger.Error("failed to shut down metrics", "error", err)
+		return err
+	}
+
+	if app.tracerProvider != nil {
+		// Flushes spans that are not exported yet
+		if err := app.tracerProvider.Shutdown(ctx); err != nil {
+			app.logger.Error("failed to shut down tracing", "error", err)
+			return err
+		}
+	}
+
 	app.logger.Info("server exited")
+
+	// Logs are closed last, every step above may log
+	return app.logs.Close()
+}
+
+// subscribeInvalidations listens for data change notifications until ctx is done.
+// The subscription is retried while Redis is unavailable.
+func (app *Application) subscribeInvalidations(ctx context.Context) {
+	defer app.background.Done()
+
+	for {
+		if err := app.redisCache.SubscribeInvalidations(ctx, app.logger, app.invalidate); err != nil {
+			app.logger.Warn("cache invalidation subscription failed, retrying",
+				"error", err.Error(),
+				"retry_in", app.retryInterval.String(),
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(app.retryInterval):
+		}
+	}
+}
+
+// invalidate refreshes the snapshot and drops cached configurations affected by the change.
+// The snapshot is refreshed first so that dropped entries are not cached again from stale data.
+// The refresh drops entries of the changes it finds by itself, unless a periodic refresh has
+// already seen them; the published invalidation is applied anyway and starts the warm-up.
+func (app *Application) invalidate(ctx context.Context, invalidation cache.Invalidation) {
+	if err := app.RefreshSnapshot(ctx); err != nil {
+		app.logger.Error("failed to refresh snapshot on invalidation", "error", err.Error())
+	}
+
+	if err := app.invalidateCache(ctx, invalidation); err != nil {
+		app.logger.Error("failed to invalidate cache",
+			"error", err.Error(),
+			"platforms", invalidation.Platforms,
+		)
+		return
+	}
+
+	app.logger.Info("cache invalidated",
+		"platforms", invalidation.Platforms,
+		"resources", invalidation.Resources,
+	)
+
+	if app.warmer != nil {
+		app.warmupInBackground(ctx, invalidation.Platforms)
+	}
+}
+
+// invalidateChanges drops cached configurations built from data changed by a snapshot reload.
+// Unlike published invalidations, it does not start a warm-up: reloads run outside the
+// application lifecycle and may happen while it shuts down.
+func (app *Application) invalidateChanges(ctx context.Context, diff *storage.DatasetDiff) {
+	invalidation := cache.InvalidationFor(diff)
+	if err := app.invalidateCache(ctx, invalidation); err != nil {
+		app.logger.Error("failed to invalidate cache after data change",
+			"error", err.Error(),
+			"platforms", invalidation.Platforms,
+		)
+		return
+	}
+
+	app.logger.Info("cache invalidated after data change",
+		"platforms", invalidation.Platforms,
+		"resources", invalidation.Resources,
+	)
+}
+
+// warmupInBackground runs warm-up without blocking the caller, it stops on shutdown
+func (app *Application) warmupInBackground(ctx context.Context, platforms []string) {
+	app.background.Add(1)
+	go func() {
+		defer app.background.Done()
+		app.warmup(ctx, platforms)
+	}()
+}
+
+// warmup loads configurations of the platforms into cache, empty list warms up all platforms
+func (app *Application) warmup(ctx context.Context, platforms []string) {
+	ctx, cancel := context.WithTimeout(ctx, app.warmupTimeout)
+	defer cancel()
+
+	started := time.Now()
+	result, err := app.warmer.Warmup(ctx, platforms)
+	if err != nil {
+		app.logger.Warn("cache warm-up interrupted",
+			"error", err.Error(),
+			"platforms", platforms,
+			"warmed", result.Warmed,
+		)
+		return
+	}
+
+	app.logger.Info("cache warmed up",
+		"platforms", platforms,
+		"warmed", result.Warmed,
+		"skipped", result.Skipped,
+		"failed", result.Failed,
+		"duration", time.Since(started).String(),
+	)
+}
+
+// invalidateCache drops cached entries affected by the change. When only assets have changed,
+// configurations of unchanged assets versions are kept.
+func (app *Application) invalidateCache(ctx context.Context, invalidation cache.Invalidation) error {
+	assetsOnly := len(invalidation.Resources) == 1 && invalidation.Resources[0] == storage.AssetsTable
+	if !assetsOnly || invalidation.AllPlatforms() {
+		return app.configService.Invalidate(ctx, invalidation.Platforms)
+	}
+
+	for _, platform := range invalidation.Platforms {
+		if err := app.configService.InvalidateAssets(ctx, platform, invalidation.AssetsVersions[platform]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -4,6 +4,9 @@ import (
 	"context"
 	"log/slog"
 
+	"sw-config-api/internal/logging"
+	"sw-config-api/internal/storage"
+
 	"github.com/sethvargo/go-envconfig"
 )
 
@@ -11,15 +14,113 @@ type Config struct {
 	DBHost     string `env:"DB_HOST,default=localhost"`
 	DBPort     string `env:"DB_PORT,default=3306"`
 	DBUser     string `env:"DB_USER,default=root"`
-	DBPassword string `env:"DB_PASSWORD,default="`
+	DBPassword string `env:"DB_PASSWORD,default=" secret:"true"`
 	DBName     string `env:"DB_NAME,default=sw_config"`
+	DBSSLMode  string `env:"DB_SSL_MODE,default=disable"` // PostgreSQL only
 	ServerAddr string `env:"SERVER_ADDR,default=:8080"`
 
+	// Limits of the API server
+	HTTPReadTimeout       int `env:"HTTP_READ_TIMEOUT_SECONDS,default=15"`
+	HTTPReadHeaderTimeout int `env:"HTTP_READ_HEADER_TIMEOUT_SECONDS,default=5"`
+	HTTPWriteTimeout      int `env:"HTTP_WRITE_TIMEOUT_SECONDS,default=15"`
+	HTTPIdleTimeout       int `env:"HTTP_IDLE_TIMEOUT_SECONDS,default=60"`
+	HTTPMaxHeaderBytes    int `env:"HTTP_MAX_HEADER_BYTES,default=1048576"`
+
+	// HTTP/2 is negotiated over TLS, h2c serves it over cleartext connections
+	HTTP2Enabled bool `env:"HTTP2_ENABLED,default=true"`
+	H2CEnabled   bool `env:"H2C_ENABLED,default=false"`
+
+	// TLS termination, enabled when the certificate file is set. Files are reloaded when they change.
+	TLSCertFile       string `env:"TLS_CERT_FILE,default="`
+	TLSKeyFile        string `env:"TLS_KEY_FILE,default="`
+	TLSReloadInterval int    `env:"TLS_RELOAD_INTERVAL_SECONDS,default=60"`
+	TLSClientAuth     string `env:"TLS_CLIENT_AUTH,default=none"` // none, verify_if_given or require
+	TLSClientCAFile   string `env:"TLS_CLIENT_CA_FILE,default="`  // CA of client certificates
+
+	// Listener of metrics, pprof and other operational endpoints, empty disables it.
+	// Only local by default, other interfaces must be listed explicitly, e.g. :8081.
+	AdminAddr string `env:"ADMIN_ADDR,default=127.0.0.1:8081"`
+
+	// Listener of the gRPC API for backend services, disabled by default. It uses TLS of the API
+	// server when enabled, but is not rate limited, so it must not be reachable by clients.
+	GRPCAddr              string `env:"GRPC_ADDR,default="`
+	GRPCReflectionEnabled bool   `env:"GRPC_REFLECTION_ENABLED,default=false"` // Describe services to grpcurl
+
+	// Time between failing readiness and draining connections on shutdown
+	ShutdownDelay int `env:"SHUTDOWN_DELAY_SECONDS,default=0"`
+
+	// Storage configuration
+	StorageDriver         string `env:"STORAGE_DRIVER,default=mysql"`                       // mysql, postgres or file
+	StorageDir            string `env:"STORAGE_DIR,default=deployments/db/migrations/data"` // Data directory for file driver
+	StorageReloadInterval int    `env:"STORAGE_RELOAD_INTERVAL_SECONDS,default=5"`          // 0 disables reloading
+	MigrateOnStart        bool   `env:"MIGRATE_ON_START,default=false"`                     // Apply pending migrations on startup
+
+	// Snapshot mode resolves configuration from an in-memory copy of the database
+	SnapshotEnabled         bool `env:"SNAPSHOT_ENABLED,default=false"`
+	SnapshotRefreshInterval int  `env:"SNAPSHOT_REFRESH_INTERVAL_SECONDS,default=30"` // 0 disables periodic refresh
+
 	// Redis configuration
 	RedisAddr     string `env:"REDIS_ADDR,default=localhost:6379"`
-	RedisPassword string `env:"REDIS_PASSWORD,default="`
+	RedisPassword string `env:"REDIS_PASSWORD,default=" secret:"true"`
 	RedisDB       int    `env:"REDIS_DB,default=0"`
-	CacheTTL      int    `env:"CACHE_TTL_SECONDS,default=300"` // 5 minutes default
+
+	RedisReadTimeout      int `env:"REDIS_READ_TIMEOUT_MS,default=100"`      // Deadline of a single Redis read, independent of HTTP timeouts
+	RedisWriteTimeout     int `env:"REDIS_WRITE_TIMEOUT_MS,default=200"`     // Deadline of a single Redis write
+	RedisFailureThreshold int `env:"REDIS_FAILURE_THRESHOLD,default=3"`      // Consecutive failures before Redis is bypassed
+	RedisCheckInterval    int `env:"REDIS_CHECK_INTERVAL_SECONDS,default=5"` // How often unavailable Redis is checked
+	CacheTTL              int `env:"CACHE_TTL_SECONDS,default=300"`          // 5 minutes default
+	CacheStaleTTL         int `env:"CACHE_STALE_TTL_SECONDS,default=3600"`   // How long expired configurations are served while refreshing
+
+	// In-process cache in front of Redis
+	LocalCacheEnabled    bool `env:"LOCAL_CACHE_ENABLED,default=false"`
+	LocalCacheMaxEntries int  `env:"LOCAL_CACHE_MAX_ENTRIES,default=1000"`
+	LocalCacheTTL        int  `env:"LOCAL_CACHE_TTL_SECONDS,default=5"`
+
+	// Lock in Redis so that only one instance recomputes a missing configuration
+	CacheLockEnabled bool `env:"CACHE_LOCK_ENABLED,default=false"`
+	CacheLockTTL     int  `env:"CACHE_LOCK_TTL_MS,default=3000"`
+	CacheLockWait    int  `env:"CACHE_LOCK_WAIT_MS,default=500"` // How long other instances wait for the value
+
+	// Drop cached configurations when data changes are published to Redis
+	CacheInvalidationEnabled bool `env:"CACHE_INVALIDATION_ENABLED,default=true"`
+
+	// Pre-populate cache for every platform and MAJOR.MINOR on startup and after invalidation
+	WarmupEnabled     bool `env:"WARMUP_ENABLED,default=false"`
+	WarmupBlocking    bool `env:"WARMUP_BLOCKING,default=false"` // Accept requests only after the startup warm-up
+	WarmupConcurrency int  `env:"WARMUP_CONCURRENCY,default=4"`  // Configurations loaded at a time
+	WarmupTimeout     int  `env:"WARMUP_TIMEOUT_SECONDS,default=30"`
+
+	// Compression of API responses negotiated by Accept-Encoding
+	CompressionEnabled bool `env:"COMPRESSION_ENABLED,default=true"`
+	CompressionMinSize int  `env:"COMPRESSION_MIN_SIZE_BYTES,default=1024"` // Smaller responses are not worth compressing
+
+	// HTTP caching headers of configurations for a CDN in front of the service
+	HTTPCacheEnabled      bool   `env:"HTTP_CACHE_ENABLED,default=false"`
+	HTTPCacheControl      string `env:"HTTP_CACHE_CONTROL,default=public, max-age=60"`
+	HTTPCacheControlStale string `env:"HTTP_CACHE_CONTROL_STALE,default=no-cache"` // Cache-Control of configurations served stale
+	HTTPVary              string `env:"HTTP_VARY,default="`                        // Request headers besides Accept-Encoding the response depends on
+
+	// Per-client rate limiting with a token bucket
+	RateLimitEnabled        bool   `env:"RATE_LIMIT_ENABLED,default=false"`
+	RateLimitKey            string `env:"RATE_LIMIT_KEY,default=ip"`            // ip, device or platform
+	RateLimitBackend        string `env:"RATE_LIMIT_BACKEND,default=memory"`    // memory (per instance) or redis (shared)
+	RateLimitPerSecond      int    `env:"RATE_LIMIT_PER_SECOND,default=10"`     // Sustained requests per second of a client
+	RateLimitBurst          int    `env:"RATE_LIMIT_BURST,default=20"`          // Requests a client can make at once
+	RateLimitMaxKeys        int    `env:"RATE_LIMIT_MAX_KEYS,default=100000"`   // Clients tracked in memory
+	RateLimitTrustedProxies int    `env:"RATE_LIMIT_TRUSTED_PROXIES,default=0"` // Proxies appending to X-Forwarded-For, 0 ignores it
+
+	// Requests served at once, more are rejected with 503. 0 disables load shedding.
+	MaxInFlightRequests int `env:"MAX_IN_FLIGHT_REQUESTS,default=0"`
+
+	// Logging: level can also be changed at runtime with PUT /log-level on ADMIN_ADDR
+	LogLevel                string `env:"LOG_LEVEL,default=info"`                 // debug, info, warn or error
+	LogFormat               string `env:"LOG_FORMAT,default=json"`                // json or text
+	LogOutput               string `env:"LOG_OUTPUT,default=stdout"`              // stdout, stderr or a file path
+	LogRequestSamplePercent int    `env:"LOG_REQUEST_SAMPLE_PERCENT,default=100"` // Requests with start/completion lines, failures are always logged
+
+	// Span export: none, otlp or stdout. OTLP is configured by OTEL_EXPORTER_OTLP_* variables.
+	TracingExporter      string `env:"TRACING_EXPORTER,default=none"`
+	TracingSamplePercent int    `env:"TRACING_SAMPLE_PERCENT,default=100"` // Share of new traces recorded
 }
 
 func LoadConfig(ctx context.Context) (*Config, error) {
@@ -33,8 +134,39 @@ func LoadConfig(ctx context.Context) (*Config, error) {
 		"db_port", config.DBPort,
 		"db_name", config.DBName,
 		"server_addr", config.ServerAddr,
+		"admin_addr", config.AdminAddr,
+		"grpc_addr", config.GRPCAddr,
+		"tls_enabled", config.TLSCertFile != "",
+		"storage_driver", config.StorageDriver,
+		"migrate_on_start", config.MigrateOnStart,
+		"snapshot_enabled", config.SnapshotEnabled,
 		"redis_addr", config.RedisAddr,
-		"cache_ttl_seconds", config.CacheTTL)
+		"cache_ttl_seconds", config.CacheTTL,
+		"local_cache_enabled", config.LocalCacheEnabled,
+		"warmup_enabled", config.WarmupEnabled,
+		"tracing_exporter", config.TracingExporter)
 
 	return &config, nil
 }
+
+// LoggingConfig returns log output settings
+func (c *Config) LoggingConfig() logging.Config {
+	return logging.Config{
+		Level:  c.LogLevel,
+		Format: c.LogFormat,
+		Output: c.LogOutput,
+	}
+}
+
+// StorageConfig returns database connection settings
+func (c *Config) StorageConfig() *storage.Config {
+	return &storage.Config{
+		Driver:   c.StorageDriver,
+		Host:     c.DBHost,
+		Port:     c.DBPort,
+		User:     c.DBUser,
+		Password: c.DBPassword,
+		DBName:   c.DBName,
+		SSLMode:  c.DBSSLMode,
+	}
+}
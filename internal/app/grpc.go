@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"time"
+
+	"sw-config-api/internal/grpcapi"
+	"sw-config-api/internal/middleware"
+	"sw-config-api/internal/requestid"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+// newGRPCServer creates the gRPC API server. Interceptors mirror the middleware of the REST API:
+// request IDs, trace propagation, metrics, panic recovery, error mapping and logging, in this order.
+// With tlsConfig set connections are served over TLS with the certificate of the API server.
+func newGRPCServer(config *Config, tlsConfig *tls.Config, configService grpcapi.ConfigServiceServer, healthServer *health.Server, logger *slog.Logger) (*grpc.Server, error) {
+	metricsInterceptor, err := middleware.GRPCMetrics(otel.Meter("sw-config-api/grpc"))
+	if err != nil {
+		return nil, err
+	}
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryServerInterceptor,
+			middleware.GRPCTracePropagation,
+			metricsInterceptor,
+			middleware.GRPCRecovery(logger),
+			middleware.GRPCErrors,
+			middleware.GRPCLogging(logger, config.LogRequestSamplePercent),
+		),
+		grpc.ConnectionTimeout(time.Duration(config.HTTPReadHeaderTimeout) * time.Second),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: time.Duration(config.HTTPIdleTimeout) * time.Second,
+		}),
+		grpc.MaxHeaderListSize(uint32(config.HTTPMaxHeaderBytes)),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	server := grpc.NewServer(options...)
+	grpcapi.RegisterConfigServiceServer(server, configService)
+
+	// Standard health checks for grpc_health_probe, reflection for grpcurl when enabled
+	healthpb.RegisterHealthServer(server, healthServer)
+	if config.GRPCReflectionEnabled {
+		reflection.Register(server)
+	}
+
+	return server, nil
+}
+
+// shutdownGRPC stops accepting gRPC connections and waits for running requests
+// like http.Server.Shutdown, requests still running when ctx is done are cancelled
+func (app *Application) shutdownGRPC(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		app.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		app.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	apperr "sw-config-api/internal/errors"
+	"sw-config-api/internal/grpcapi"
+	"sw-config-api/internal/service"
+)
+
+// configServiceFunc adapts a function to service.ConfigServiceInterface
+type configServiceFunc func(ctx context.Context, params service.ClientParams) (*service.Configuration, error)
+
+func (f configServiceFunc) GetConfiguration(ctx context.Context, params service.ClientParams) (*service.Configuration, error) {
+	return f(ctx, params)
+}
+
+func TestGRPCServer_GetConfig(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var received service.ClientParams
+	configService := configServiceFunc(func(ctx context.Context, params service.ClientParams) (*service.Configuration, error) {
+		received = params
+		switch params.Platform {
+		case "android":
+			return &service.Configuration{
+				Version: service.VersionInfo{Required: "14.0.0", Store: "14.8.447"},
+				Assets:  service.Resource{Version: "14.8.447", Hash: "abc", Urls: []string{"cdn.example.com"}},
+				Stale:   true,
+			}, nil
+		case "ios":
+			return nil, &apperr.NotFoundError{Platform: params.Platform, AppVersion: params.AppVersion}
+		case "tv":
+			panic("nil map")
+		default:
+			return nil, errors.New("connection refused")
+		}
+	})
+
+	config := &Config{
+		HTTPReadHeaderTimeout:   5,
+		HTTPIdleTimeout:         60,
+		HTTPMaxHeaderBytes:      1 << 20,
+		LogRequestSamplePercent: 100,
+	}
+	server, err := newGRPCServer(config, nil,
+		service.NewGRPCHandler(configService, logger), health.NewServer(), logger)
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener) // nolint:errcheck
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close() // nolint:errcheck
+	client := grpcapi.NewConfigServiceClient(conn)
+
+	t.Run("configuration", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "caller-id")
+		var header metadata.MD
+		response, err := client.GetConfig(ctx, &grpcapi.GetConfigRequest{
+			AppVersion:    "14.8.447",
+			Platform:      "android",
+			AssetsVersion: "14.8.400",
+		}, grpc.Header(&header))
+		require.NoError(t, err)
+
+		assert.Equal(t, service.ClientParams{Platform: "android", AppVersion: "14.8.447", AssetsVersion: "14.8.400"}, received)
+		assert.Equal(t, "14.0.0", response.GetConfig().GetVersion().GetRequired())
+		assert.Equal(t, []string{"cdn.example.com"}, response.GetConfig().GetAssets().GetUrls())
+		assert.True(t, response.GetStale())
+		assert.Equal(t, []string{"caller-id"}, header.Get("x-request-id"))
+	})
+
+	// requestID returns the ID of the RequestInfo detail of the status
+	requestID := func(st *status.Status) string {
+		for _, detail := range st.Details() {
+			if info, ok := detail.(*errdetails.RequestInfo); ok {
+				return info.GetRequestId()
+			}
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name    string
+		request *grpcapi.GetConfigRequest
+		code    codes.Code
+		message string
+	}{
+		{
+			name:    "missing app version",
+			request: &grpcapi.GetConfigRequest{Platform: "android"},
+			code:    codes.InvalidArgument,
+			message: "Missing required parameter: appVersion",
+		},
+		{
+			name:    "invalid definitions version",
+			request: &grpcapi.GetConfigRequest{AppVersion: "14.8.447", Platform: "android", DefinitionsVersion: "14.8"},
+			code:    codes.InvalidArgument,
+			message: "Invalid parameter: definitionsVersion must be in MAJOR.MINOR.PATCH format",
+		},
+		{
+			// The server keeps serving the following requests
+			name:    "panic is recovered",
+			request: &grpcapi.GetConfigRequest{AppVersion: "14.8.447", Platform: "tv"},
+			code:    codes.Internal,
+			message: "Internal server error",
+		},
+		{
+			name:    "not found",
+			request: &grpcapi.GetConfigRequest{AppVersion: "14.8.447", Platform: "ios"},
+			code:    codes.NotFound,
+			message: "Configuration not found",
+		},
+		{
+			name:    "internal error details are not exposed",
+			request: &grpcapi.GetConfigRequest{AppVersion: "14.8.447", Platform: "web"},
+			code:    codes.Internal,
+			message: "Internal server error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header metadata.MD
+			_, err := client.GetConfig(context.Background(), tt.request, grpc.Header(&header))
+
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.message, st.Message())
+
+			// A new ID is generated, and the error carries the one sent in the header
+			require.Len(t, header.Get("x-request-id"), 1)
+			assert.Equal(t, header.Get("x-request-id")[0], requestID(st))
+		})
+	}
+}
+
+func TestApplication_Start_ClosesListenersWhenGRPCAddrIsTaken(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer taken.Close() // nolint:errcheck
+
+	// A free port for the API listener
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	apiAddr := free.Addr().String()
+	require.NoError(t, free.Close())
+
+	app := &Application{
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		config:     &Config{GRPCAddr: taken.Addr().String()},
+		httpServer: &http.Server{Addr: apiAddr},
+		grpcServer: grpc.NewServer(),
+	}
+	require.Error(t, app.Start())
+
+	// The API listener bound before the failure is released and the HTTP server is not started
+	assert.Never(t, func() bool {
+		conn, err := net.Dial("tcp", apiAddr)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 100*time.Millisecond, 10*time.Millisecond)
+	assert.False(t, app.ready.Load())
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Components reported by health checks
+const (
+	componentStorage    = "storage"
+	componentStatements = "statements"
+	componentCache      = "cache"
+)
+
+// Dependency and readiness statuses returned by /readyz
+const (
+	statusUp       = "up"
+	statusDown     = "down"
+	statusReady    = "ready"
+	statusNotReady = "not_ready"
+)
+
+// healthCheckTimeout limits a single dependency check
+const healthCheckTimeout = time.Second
+
+// checkStorage returns an error if the database is unreachable.
+// Storage without a database (files) is always healthy.
+func (app *Application) checkStorage(ctx context.Context) error {
+	if app.db == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return app.db.PingContext(ctx)
+}
+
+// checkStatements returns an error if a prepared statement no longer matches the schema
+func (app *Application) checkStatements(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, checker := range app.statements {
+		errs = append(errs, checker.CheckStatements(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// checkCache pings Redis bypassing the circuit breaker
+func (app *Application) checkCache(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return app.redisCache.Ping(ctx)
+}
+
+// cacheHealthy reports whether Redis is available. The service works without it, so
+// cache health is reported separately and does not make the service unhealthy.
+func (app *Application) cacheHealthy() bool {
+	return app.redisCache.Healthy()
+}
+
+// registerHealthMetrics reports availability of storage and cache as 1 or 0
+func (app *Application) registerHealthMetrics(meter metric.Meter) error {
+	up, err := meter.Int64ObservableGauge(
+		"health.up",
+		metric.WithDescription("Availability of service dependencies, 1 when available"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		storageUp := int64(0)
+		if app.checkStorage(ctx) == nil {
+			storageUp = 1
+		}
+		observer.ObserveInt64(up, storageUp, metric.WithAttributes(attribute.String("component", componentStorage)))
+
+		cacheUp := int64(0)
+		if app.cacheHealthy() {
+			cacheUp = 1
+		}
+		observer.ObserveInt64(up, cacheUp, metric.WithAttributes(attribute.String("component", componentCache)))
+		return nil
+	}, up)
+	return err
+}
+
+// dependencyStatus is the state of a single dependency in the readiness response
+type dependencyStatus struct {
+	Status   string `json:"status"`
+	Required bool   `json:"required"` // Whether the dependency affects readiness
+	Error    string `json:"error,omitempty"`
+}
+
+// readinessResponse is the body of /readyz
+type readinessResponse struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]dependencyStatus `json:"dependencies,omitempty"`
+}
+
+// handleLiveness reports that the process is alive and serving HTTP
+func (app *Application) handleLiveness(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadiness checks dependencies and reports whether the instance can serve traffic.
+// The service keeps working without Redis, so the cache is reported but does not affect readiness.
+func (app *Application) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	// Not ready while warming up or shutting down, dependencies are not checked
+	if !app.ready.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, readinessResponse{Status: statusNotReady})
+		return
+	}
+
+	response := readinessResponse{
+		Status: statusReady,
+		Dependencies: map[string]dependencyStatus{
+			componentStorage:    newDependencyStatus(app.checkStorage(r.Context()), true),
+			componentStatements: newDependencyStatus(app.checkStatements(r.Context()), true),
+			componentCache:      newDependencyStatus(app.checkCache(r.Context()), false),
+		},
+	}
+
+	code := http.StatusOK
+	for _, dependency := range response.Dependencies {
+		if dependency.Required && dependency.Status != statusUp {
+			response.Status = statusNotReady
+			code = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, code, response)
+}
+
+// newDependencyStatus converts a check result to a dependency status
+func newDependencyStatus(err error, required bool) dependencyStatus {
+	if err != nil {
+		return dependencyStatus{Status: statusDown, Required: required, Error: err.Error()}
+	}
+	return dependencyStatus{Status: statusUp, Required: required}
+}
+
+// writeJSON writes a health or admin response as JSON, responses are never cached
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sw-config-api/internal/cache"
+)
+
+func TestReadiness(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Nothing listens on port 1, so the cache is down
+	redisCache := cache.NewResilientRedisCache(cache.RedisOptions{
+		Addr:             "127.0.0.1:1",
+		ReadTimeout:      50 * time.Millisecond,
+		WriteTimeout:     50 * time.Millisecond,
+		FailureThreshold: 1,
+		CheckInterval:    time.Hour,
+	}, logger)
+	defer redisCache.Close() // nolint:errcheck
+
+	app := &Application{logger: logger, redisCache: redisCache}
+	readiness := func() (int, readinessResponse) {
+		recorder := httptest.NewRecorder()
+		app.handleReadiness(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var response readinessResponse
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		return recorder.Code, response
+	}
+
+	// Not ready before startup
+	code, response := readiness()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, statusNotReady, response.Status)
+
+	// Storage without a database is ready, the cache is optional
+	app.ready.Store(true)
+	code, response = readiness()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, statusReady, response.Status)
+	assert.Equal(t, statusUp, response.Dependencies[componentStorage].Status)
+	assert.Equal(t, statusDown, response.Dependencies[componentCache].Status)
+	assert.False(t, response.Dependencies[componentCache].Required)
+	assert.NotEmpty(t, response.Dependencies[componentCache].Error)
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"sw-config-api/internal/cache"
+	"sw-config-api/internal/middleware"
+	"sw-config-api/internal/ratelimit"
+
+	"go.opentelemetry.io/otel"
+)
+
+// Rate limiter backends selected by RATE_LIMIT_BACKEND
+const (
+	rateLimitBackendMemory = "memory"
+	rateLimitBackendRedis  = "redis"
+)
+
+// limitRequests wraps the API with load shedding and per-client rate limiting.
+// Load is shed first, so that rejecting requests costs as little as possible.
+func limitRequests(config *Config, next http.Handler, redisCache *cache.RedisCache, logger *slog.Logger) (http.Handler, error) {
+	meter := otel.Meter("sw-config-api/http")
+
+	if config.RateLimitEnabled {
+		key, err := middleware.RateLimitKey(config.RateLimitKey, config.RateLimitTrustedProxies)
+		if err != nil {
+			return nil, err
+		}
+
+		if config.RateLimitPerSecond <= 0 || config.RateLimitBurst <= 0 {
+			return nil, fmt.Errorf("rate limit must be positive: %d per second, burst %d", config.RateLimitPerSecond, config.RateLimitBurst)
+		}
+
+		limit := ratelimit.Limit{Rate: float64(config.RateLimitPerSecond), Burst: config.RateLimitBurst}
+		var limiter ratelimit.Limiter = ratelimit.NewMemoryLimiter(limit, config.RateLimitMaxKeys)
+		switch config.RateLimitBackend {
+		case rateLimitBackendMemory:
+		case rateLimitBackendRedis:
+			// Instances limit clients on their own while Redis is unavailable
+			limiter = ratelimit.NewRedisLimiter(redisCache, limit, limiter, logger)
+		default:
+			return nil, fmt.Errorf("unsupported rate limit backend: %s", config.RateLimitBackend)
+		}
+
+		rateLimit, err := middleware.RateLimit(limiter, key, meter, logger)
+		if err != nil {
+			return nil, err
+		}
+		next = rateLimit(next)
+	}
+
+	if config.MaxInFlightRequests > 0 {
+		concurrencyLimit, err := middleware.ConcurrencyLimit(config.MaxInFlightRequests, meter)
+		if err != nil {
+			return nil, err
+		}
+		next = concurrencyLimit(next)
+	}
+
+	return next, nil
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"sw-config-api/internal/storage"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrMigrateUsage is returned when the migrate command is called with invalid arguments
+var ErrMigrateUsage = errors.New("usage: sw-config-api migrate up|down|status")
+
+// prepareSchema applies pending migrations when MIGRATE_ON_START is enabled
+// and verifies that the database schema is not older than the code expects
+func prepareSchema(ctx context.Context, db *sqlx.DB, config *Config) error {
+	migrator, err := storage.NewMigrator(db, config.StorageDriver)
+	if err != nil {
+		return err
+	}
+
+	if config.MigrateOnStart {
+		results, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("database migrations applied", "count", len(results))
+	}
+
+	if err := migrator.CheckVersion(ctx); err != nil {
+		if errors.Is(err, storage.ErrSchemaOutdated) {
+			return fmt.Errorf("%w, run `sw-config-api migrate up` or set MIGRATE_ON_START=true", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Migrate runs the migrate subcommand: up applies pending migrations,
+// down rolls back the latest one and status prints the state of all migrations
+func Migrate(ctx context.Context, config *Config, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return ErrMigrateUsage
+	}
+	command := args[0]
+	if command != "up" && command != "down" && command != "status" {
+		return ErrMigrateUsage
+	}
+
+	db, err := storage.New(config.StorageConfig())
+	if err != nil {
+		return err
+	}
+	defer db.Close() // nolint:errcheck
+
+	migrator, err := storage.NewMigrator(db, config.StorageDriver)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		results, err := migrator.Up(ctx)
+		for _, result := range results {
+			fmt.Fprintln(out, result)
+		}
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+	case "down":
+		result, err := migrator.Down(ctx)
+		if result != nil {
+			fmt.Fprintln(out, result)
+		}
+		if err != nil {
+			return err
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tMIGRATION\tSTATE\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "-"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", status.Source.Version, status.Source.Path, status.State, appliedAt)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// newHTTPServer creates the API server with configured timeouts and protocols.
+// With TLS files configured it serves HTTPS and returns the reloader of the certificate.
+func newHTTPServer(config *Config, logger *slog.Logger) (*http.Server, *certReloader, error) {
+	server := &http.Server{
+		Addr:              config.ServerAddr,
+		ReadTimeout:       time.Duration(config.HTTPReadTimeout) * time.Second,
+		ReadHeaderTimeout: time.Duration(config.HTTPReadHeaderTimeout) * time.Second,
+		WriteTimeout:      time.Duration(config.HTTPWriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(config.HTTPIdleTimeout) * time.Second,
+		MaxHeaderBytes:    config.HTTPMaxHeaderBytes,
+		Protocols:         new(http.Protocols),
+	}
+
+	server.Protocols.SetHTTP1(true)
+	server.Protocols.SetHTTP2(config.HTTP2Enabled)
+	if config.TLSCertFile == "" {
+		// Cleartext HTTP/2 for callers behind a proxy terminating TLS
+		server.Protocols.SetUnencryptedHTTP2(config.HTTP2Enabled && config.H2CEnabled)
+		return server, nil, nil
+	}
+
+	reloader, err := newCertReloader(config.TLSCertFile, config.TLSKeyFile, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	server.TLSConfig, err = newTLSConfig(reloader, config.TLSClientAuth, config.TLSClientCAFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return server, reloader, nil
+}
@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"sw-config-api/internal/service"
+	"sw-config-api/internal/storage"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// snapshotRefresher reloads an in-memory snapshot on demand
+type snapshotRefresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// repositories holds storage implementations selected by configuration
+type repositories struct {
+	db               *sqlx.DB // nil when storage is not backed by a database
+	assets           service.ResourceRepo
+	definitions      service.ResourceRepo
+	assetURLs        service.URLRepo
+	definitionURLs   service.URLRepo
+	platformVersions service.PlatformVersionRepository
+	entryPoints      service.EntryPointRepository
+	versionLines     service.VersionLineRepository
+	statements       []storage.StatementChecker // repositories with prepared statements
+	queried          bool                       // data is resolved by database queries on every cache miss
+	snapshots        *storage.SnapshotStore     // nil when data is resolved by database queries
+	refresher        snapshotRefresher          // nil when the snapshot cannot be refreshed on demand
+	closer           io.Closer
+}
+
+// newRepositories creates repositories for the configured storage driver
+func newRepositories(ctx context.Context, config *Config, logger *slog.Logger) (*repositories, error) {
+	switch config.StorageDriver {
+	case storage.DriverMySQL, storage.DriverPostgres:
+		return newDatabaseRepositories(ctx, config, logger)
+	case storage.DriverFile:
+		return newFileRepositories(config, logger)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", config.StorageDriver)
+	}
+}
+
+// newDatabaseRepositories creates repositories backed by the database
+func newDatabaseRepositories(ctx context.Context, config *Config, logger *slog.Logger) (*repositories, error) {
+	db, err := storage.New(config.StorageConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := prepareSchema(ctx, db, config); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	if config.SnapshotEnabled {
+		return newDatabaseSnapshotRepositories(ctx, db, config, logger)
+	}
+
+	assetRepository, err := storage.NewResourceRepository(ctx, db, storage.AssetsTable, storage.MajorOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionRepository, err := storage.NewResourceRepository(ctx, db, storage.DefinitionsTable, storage.MajorMinor)
+	if err != nil {
+		return nil, err
+	}
+
+	assetURLRepository, err := storage.NewURLRepository(ctx, db, storage.AssetURLsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionURLRepository, err := storage.NewURLRepository(ctx, db, storage.DefinitionURLsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	platformVersionRepository, err := storage.NewPlatformVersionRepository(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	entryPointRepository, err := storage.NewEntryPointRepository(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repositories{
+		db:               db,
+		assets:           assetRepository,
+		definitions:      definitionRepository,
+		assetURLs:        assetURLRepository,
+		definitionURLs:   definitionURLRepository,
+		platformVersions: platformVersionRepository,
+		entryPoints:      entryPointRepository,
+		versionLines:     platformVersionRepository,
+		statements: []storage.StatementChecker{
+			assetRepository,
+			definitionRepository,
+			assetURLRepository,
+			definitionURLRepository,
+			entryPointRepository,
+		},
+		queried: true,
+		closer:  db,
+	}, nil
+}
+
+// newDatabaseSnapshotRepositories creates repositories that resolve data from
+// an in-memory snapshot of the database refreshed in the background
+func newDatabaseSnapshotRepositories(ctx context.Context, db *sqlx.DB, config *Config, logger *slog.Logger) (*repositories, error) {
+	databaseStore, err := storage.NewDatabaseStore(
+		ctx,
+		db,
+		time.Duration(config.SnapshotRefreshInterval)*time.Second,
+		logger,
+	)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	repos, err := newSnapshotRepositories(databaseStore.SnapshotStore)
+	if err != nil {
+		_ = databaseStore.Close()
+		_ = db.Close()
+		return nil, err
+	}
+	repos.db = db
+	repos.refresher = databaseStore
+	repos.closer = closers{databaseStore, db}
+
+	return repos, nil
+}
+
+// newFileRepositories creates repositories backed by files in a directory
+func newFileRepositories(config *Config, logger *slog.Logger) (*repositories, error) {
+	fileStore, err := storage.NewFileStore(
+		config.StorageDir,
+		time.Duration(config.StorageReloadInterval)*time.Second,
+		logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := newSnapshotRepositories(fileStore.SnapshotStore)
+	if err != nil {
+		return nil, err
+	}
+	repos.closer = fileStore
+
+	return repos, nil
+}
+
+// newSnapshotRepositories creates repositories that resolve data from an in-memory snapshot
+func newSnapshotRepositories(store *storage.SnapshotStore) (*repositories, error) {
+	assetRepository, err := storage.NewSnapshotResourceRepository(store, storage.AssetsTable, storage.MajorOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionRepository, err := storage.NewSnapshotResourceRepository(store, storage.DefinitionsTable, storage.MajorMinor)
+	if err != nil {
+		return nil, err
+	}
+
+	assetURLRepository, err := storage.NewSnapshotURLRepository(store, storage.AssetURLsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionURLRepository, err := storage.NewSnapshotURLRepository(store, storage.DefinitionURLsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	platformVersionRepository := storage.NewSnapshotPlatformVersionRepository(store)
+
+	return &repositories{
+		assets:           assetRepository,
+		definitions:      definitionRepository,
+		assetURLs:        assetURLRepository,
+		definitionURLs:   definitionURLRepository,
+		platformVersions: platformVersionRepository,
+		entryPoints:      storage.NewSnapshotEntryPointRepository(store),
+		versionLines:     platformVersionRepository,
+		snapshots:        store,
+	}, nil
+}
+
+// instrument records latency of every repository call
+func (r *repositories) instrument(instrumentation *service.RepositoryInstrumentation) {
+	r.assets = instrumentation.ResourceRepo(storage.AssetsTable, r.assets)
+	r.definitions = instrumentation.ResourceRepo(storage.DefinitionsTable, r.definitions)
+	r.assetURLs = instrumentation.URLRepo(storage.AssetURLsTable, r.assetURLs)
+	r.definitionURLs = instrumentation.URLRepo(storage.DefinitionURLsTable, r.definitionURLs)
+	r.platformVersions = instrumentation.PlatformVersionRepository(r.platformVersions)
+	r.entryPoints = instrumentation.EntryPointRepository(r.entryPoints)
+}
+
+// closers closes several resources in order and returns the first error
+type closers []io.Closer
+
+func (c closers) Close() error {
+	var firstErr error
+	for _, closer := range c {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// Client certificate policies selected by TLS_CLIENT_AUTH
+const (
+	clientAuthNone          = "none"
+	clientAuthVerifyIfGiven = "verify_if_given" // internal callers present certificates, mobile clients do not
+	clientAuthRequire       = "require"
+)
+
+// certReloader serves the certificate from files and reloads it when the files change,
+// so that renewed certificates are picked up without a restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   *slog.Logger
+
+	cert        atomic.Pointer[tls.Certificate]
+	fingerprint [2][sha256.Size]byte // hashes of the loaded files, accessed by the reloading goroutine only
+}
+
+// newCertReloader loads the certificate, failing if it cannot be loaded
+func newCertReloader(certFile, keyFile string, logger *slog.Logger) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, logger: logger}
+	if _, err := r.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// reloadIfChanged loads the certificate if the contents of the files differ from the last load.
+// Contents are compared rather than modification times, which are kept by cp -p, some secret
+// sync tools and symlink swaps of Kubernetes secrets.
+func (r *certReloader) reloadIfChanged() (bool, error) {
+	certPEM, err := os.ReadFile(r.certFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read TLS file: %w", err)
+	}
+	keyPEM, err := os.ReadFile(r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read TLS file: %w", err)
+	}
+
+	fingerprint := [2][sha256.Size]byte{sha256.Sum256(certPEM), sha256.Sum256(keyPEM)}
+	if r.cert.Load() != nil && fingerprint == r.fingerprint {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	r.cert.Store(&cert)
+	r.fingerprint = fingerprint
+	return true, nil
+}
+
+// watch checks the files every interval until ctx is done. A certificate that fails to load,
+// e.g. while only one of the files is replaced, is retried and the previous one is kept.
+func (r *certReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.reloadIfChanged()
+			if err != nil {
+				r.logger.Error("failed to reload TLS certificate, serving the previous one", "error", err.Error())
+			} else if reloaded {
+				r.logger.Info("TLS certificate reloaded", "cert_file", r.certFile)
+			}
+		}
+	}
+}
+
+// newTLSConfig creates the server TLS configuration with certificates of the reloader
+func newTLSConfig(reloader *certReloader, clientAuth, clientCAFile string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	switch clientAuth {
+	case clientAuthNone:
+		return config, nil
+	case clientAuthVerifyIfGiven:
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case clientAuthRequire:
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unsupported TLS client auth: %s", clientAuth)
+	}
+
+	pem, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	config.ClientCAs = x509.NewCertPool()
+	if !config.ClientCAs.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates in client CA file %s", clientCAFile)
+	}
+	return config, nil
+}
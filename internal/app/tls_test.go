@@ -0,0 +1,132 @@
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes a self-signed certificate with the serial number and its key
+func writeCertificate(t *testing.T, certFile, keyFile string, serial int64, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func TestHTTPServer_ReloadsCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Minute)
+	writeCertificate(t, certFile, keyFile, 1, modTime)
+
+	config := &Config{
+		HTTPReadTimeout:  5,
+		HTTPWriteTimeout: 5,
+		HTTP2Enabled:     true,
+		TLSCertFile:      certFile,
+		TLSKeyFile:       keyFile,
+		TLSClientAuth:    clientAuthNone,
+	}
+	server, reloader, err := newHTTPServer(config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	require.NotNil(t, reloader)
+	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.ServeTLS(listener, "", "") // nolint:errcheck
+	defer server.Close()                 // nolint:errcheck
+
+	// serial makes a request on a new connection and returns the serial number of the server certificate
+	serial := func() int64 {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true}, // nolint:gosec
+			ForceAttemptHTTP2: true,
+		}}
+		response, err := client.Get("https://" + listener.Addr().String() + "/healthz")
+		require.NoError(t, err)
+		defer response.Body.Close() // nolint:errcheck
+
+		assert.Equal(t, 2, response.ProtoMajor)
+		return response.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+	assert.Equal(t, int64(1), serial())
+
+	// Unchanged files are not reloaded
+	reloaded, err := reloader.reloadIfChanged()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	writeCertificate(t, certFile, keyFile, 2, modTime.Add(time.Second))
+	reloaded, err = reloader.reloadIfChanged()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, int64(2), serial())
+
+	// A broken certificate keeps the previous one
+	require.NoError(t, os.WriteFile(keyFile, []byte("broken"), 0o600))
+	require.NoError(t, os.Chtimes(keyFile, modTime.Add(2*time.Second), modTime.Add(2*time.Second)))
+	_, err = reloader.reloadIfChanged()
+	assert.Error(t, err)
+	assert.Equal(t, int64(2), serial())
+
+	// Files replaced with an older modification time, e.g. by cp -p, are reloaded too
+	writeCertificate(t, certFile, keyFile, 3, modTime.Add(-time.Hour))
+	reloaded, err = reloader.reloadIfChanged()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, int64(3), serial())
+}
+
+func TestNewTLSConfig_ClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeCertificate(t, certFile, keyFile, 1, time.Now())
+
+	reloader, err := newCertReloader(certFile, keyFile, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	config, err := newTLSConfig(reloader, clientAuthVerifyIfGiven, certFile)
+	require.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, config.ClientAuth)
+	assert.NotNil(t, config.ClientCAs)
+
+	_, err = newTLSConfig(reloader, clientAuthRequire, keyFile)
+	assert.Error(t, err, "a key is not a CA certificate")
+	_, err = newTLSConfig(reloader, "optional", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"sync"
+)
+
+// circuitBreaker stops calls to a failing dependency after several consecutive failures.
+// The breaker stays open until a health check reports the dependency available again.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	failures  int
+	open      bool
+}
+
+// newCircuitBreaker creates a breaker that opens after threshold consecutive failures
+func newCircuitBreaker(threshold int) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: max(threshold, 1),
+	}
+}
+
+// allow reports whether a call may be made
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.open
+}
+
+// success records a successful call
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+}
+
+// failure records a failed call and reports whether the breaker has just opened
+func (b *circuitBreaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.open {
+		return false
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.open = true
+		return true
+	}
+	return false
+}
+
+// close lets calls through again and reports whether the breaker was open
+func (b *circuitBreaker) close() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.open
+	b.open = false
+	b.failures = 0
+	return wasOpen
+}
@@ -1,11 +1,17 @@
 package cache
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
-// Interface defines the cache interface
+// Interface defines the cache interface.
+// Implementations stop waiting for a slow backend when ctx is done.
 type Interface interface {
-	Get(key string) ([]byte, bool)
-	Set(key string, value []byte, ttl time.Duration) error
-	Delete(key string) error
+	Get(ctx context.Context, key string) ([]byte, bool)
+	GetMulti(ctx context.Context, keys []string) map[string][]byte
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	DeletePrefix(ctx context.Context, prefix string) error
 	Close() error
 }
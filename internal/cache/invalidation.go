@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"sw-config-api/internal/storage"
+)
+
+// InvalidationChannel is the Redis pub/sub channel for configuration change notifications
+const InvalidationChannel = "sw-config:invalidate"
+
+// Invalidation describes changed configuration data
+type Invalidation struct {
+	Platforms []string `json:"platforms,omitempty"` // Changed platforms, empty means all platforms
+	Resources []string `json:"resources,omitempty"` // Changed resource types (storage table names)
+
+	// Changed and removed assets versions by platform. Configurations of other assets
+	// versions stay valid when assets are the only changed resource.
+	AssetsVersions map[string][]string `json:"assets_versions,omitempty"`
+}
+
+// AllPlatforms reports whether the change affects every platform
+func (i Invalidation) AllPlatforms() bool {
+	return len(i.Platforms) == 0
+}
+
+// InvalidationFor describes data changed by the diff.
+// URL lists and entry points are shared by all platforms, so their changes invalidate everything.
+func InvalidationFor(diff *storage.DatasetDiff) Invalidation {
+	var invalidation Invalidation
+	platforms := make(map[string]struct{})
+
+	addResources := func(tableName string, changed []string) {
+		if len(changed) == 0 {
+			return
+		}
+		invalidation.Resources = append(invalidation.Resources, tableName)
+		for _, platform := range changed {
+			platforms[platform] = struct{}{}
+		}
+	}
+	addResources(storage.AssetsTable, resourceDiffPlatforms(diff.Assets))
+	invalidation.AssetsVersions = resourceDiffVersions(diff.Assets)
+	addResources(storage.DefinitionsTable, resourceDiffPlatforms(diff.Definitions))
+	addResources(storage.PlatformVersionsTable, platformVersionDiffPlatforms(diff.PlatformVersions))
+
+	allPlatforms := false
+	if !diff.AssetURLs.IsEmpty() {
+		invalidation.Resources = append(invalidation.Resources, storage.AssetURLsTable)
+		allPlatforms = true
+	}
+	if !diff.DefinitionURLs.IsEmpty() {
+		invalidation.Resources = append(invalidation.Resources, storage.DefinitionURLsTable)
+		allPlatforms = true
+	}
+	if !diff.EntryPoints.IsEmpty() {
+		invalidation.Resources = append(invalidation.Resources, storage.EntryPointsTable)
+		allPlatforms = true
+	}
+
+	if !allPlatforms {
+		for platform := range platforms {
+			invalidation.Platforms = append(invalidation.Platforms, platform)
+		}
+		sort.Strings(invalidation.Platforms)
+	}
+	return invalidation
+}
+
+// resourceDiffPlatforms lists platforms of added, changed and removed resources
+func resourceDiffPlatforms(diff storage.ResourceDiff) []string {
+	var platforms []string
+	for _, resource := range diff.Added {
+		platforms = append(platforms, resource.Platform)
+	}
+	for _, change := range diff.Changed {
+		platforms = append(platforms, change.Platform)
+	}
+	for _, resource := range diff.Removed {
+		platforms = append(platforms, resource.Platform)
+	}
+	return platforms
+}
+
+// resourceDiffVersions lists changed and removed resource versions by platform.
+// Added versions have nothing cached yet.
+func resourceDiffVersions(diff storage.ResourceDiff) map[string][]string {
+	if len(diff.Changed) == 0 && len(diff.Removed) == 0 {
+		return nil
+	}
+
+	versions := make(map[string][]string)
+	for _, change := range diff.Changed {
+		versions[change.Platform] = append(versions[change.Platform], change.Version)
+	}
+	for _, resource := range diff.Removed {
+		versions[resource.Platform] = append(versions[resource.Platform], resource.Version)
+	}
+	return versions
+}
+
+// platformVersionDiffPlatforms lists platforms with added, changed and removed versions
+func platformVersionDiffPlatforms(diff storage.PlatformVersionDiff) []string {
+	var platforms []string
+	for _, value := range diff.Added {
+		platforms = append(platforms, value.Platform)
+	}
+	for _, change := range diff.Changed {
+		platforms = append(platforms, change.Platform)
+	}
+	for _, value := range diff.Removed {
+		platforms = append(platforms, value.Platform)
+	}
+	return platforms
+}
+
+// PublishInvalidation notifies all subscribed instances about changed configuration data
+func (r *RedisCache) PublishInvalidation(ctx context.Context, invalidation Invalidation) error {
+	payload, err := json.Marshal(invalidation)
+	if err != nil {
+		return fmt.Errorf("failed to encode invalidation: %w", err)
+	}
+
+	err = r.call(ctx, r.writeTimeout, func(ctx context.Context) error {
+		return r.client.Publish(ctx, InvalidationChannel, payload).Err()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish invalidation: %w", err)
+	}
+	return nil
+}
+
+// SubscribeInvalidations calls handler for every published invalidation until ctx is done.
+// Connection failures are retried by the Redis client; notifications published
+// while disconnected are lost and the affected entries expire by TTL.
+func (r *RedisCache) SubscribeInvalidations(ctx context.Context, logger *slog.Logger, handler func(context.Context, Invalidation)) error {
+	pubsub := r.client.Subscribe(ctx, InvalidationChannel)
+	defer pubsub.Close() // nolint:errcheck
+
+	// Wait for subscription confirmation so that errors are reported to the caller
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", InvalidationChannel, err)
+	}
+	logger.Info("subscribed to cache invalidations", "channel", InvalidationChannel)
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var invalidation Invalidation
+			if err := json.Unmarshal([]byte(message.Payload), &invalidation); err != nil {
+				logger.Error("invalid cache invalidation message",
+					"error", err.Error(),
+					"payload", message.Payload,
+				)
+				continue
+			}
+			handler(ctx, invalidation)
+		}
+	}
+}
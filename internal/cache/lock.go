@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/xid"
+)
+
+// Locker acquires short-lived locks shared between service instances
+type Locker interface {
+	// TryLock acquires the lock without waiting. The lock expires after ttl
+	// unless it is released earlier with the returned unlock function.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), acquired bool, err error)
+}
+
+// unlockScript deletes the lock only if it is still held by the same owner
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// TryLock acquires a Redis lock with SET NX
+func (r *RedisCache) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token := xid.New().String()
+
+	var acquired bool
+	err := r.call(ctx, r.writeTimeout, func(ctx context.Context) error {
+		var err error
+		acquired, err = r.client.SetNX(ctx, key, token, ttl).Result()
+		return err
+	})
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+
+	unlock := func() {
+		// The lock expires by itself if it cannot be released
+		_ = r.call(context.Background(), r.writeTimeout, func(ctx context.Context) error {
+			return unlockScript.Run(ctx, r.client, []string{key}, token).Err()
+		})
+	}
+	return unlock, true, nil
+}
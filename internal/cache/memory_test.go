@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemoryCache(2)
+
+	require.NoError(t, memory.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, memory.Set(ctx, "b", []byte("2"), 0))
+
+	// Reading a makes b the least recently used entry
+	_, ok := memory.Get(ctx, "a")
+	require.True(t, ok)
+
+	require.NoError(t, memory.Set(ctx, "c", []byte("3"), 0))
+
+	_, ok = memory.Get(ctx, "b")
+	assert.False(t, ok)
+	value, ok := memory.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, memory.Len())
+}
+
+func TestMemoryCache_ExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	memory := NewMemoryCache(10)
+	memory.now = func() time.Time { return now }
+
+	require.NoError(t, memory.Set(ctx, "key", []byte("value"), time.Second))
+	_, ok := memory.Get(ctx, "key")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = memory.Get(ctx, "key")
+	assert.False(t, ok)
+	assert.Equal(t, 0, memory.Len())
+}
+
+func TestMemoryCache_DeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemoryCache(10)
+	require.NoError(t, memory.Set(ctx, "config:android:14.0.0::", []byte("1"), 0))
+	require.NoError(t, memory.Set(ctx, "config:android:13.0.0::", []byte("2"), 0))
+	require.NoError(t, memory.Set(ctx, "config:ios:14.0.0::", []byte("3"), 0))
+
+	require.NoError(t, memory.DeletePrefix(ctx, "config:android:"))
+
+	_, ok := memory.Get(ctx, "config:android:14.0.0::")
+	assert.False(t, ok)
+	_, ok = memory.Get(ctx, "config:ios:14.0.0::")
+	assert.True(t, ok)
+}
+
+func TestTieredCache_FallsBackToRemote(t *testing.T) {
+	ctx := context.Background()
+	local := NewMemoryCache(10)
+	remote := NewMemoryCache(10)
+	tiered, err := NewTieredCache(local, remote, time.Minute, noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	require.NoError(t, remote.Set(ctx, "key", []byte("value"), time.Hour))
+
+	value, ok := tiered.Get(ctx, "key")
+	require.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	// Remote hit is copied to the local tier
+	value, ok = local.Get(ctx, "key")
+	require.True(t, ok)
+	assert.Equal(t, []byte("value"), value)
+
+	require.NoError(t, tiered.DeletePrefix(ctx, "k"))
+	_, ok = local.Get(ctx, "key")
+	assert.False(t, ok)
+	_, ok = remote.Get(ctx, "key")
+	assert.False(t, ok)
+}
+
+func TestTieredCache_GetMulti(t *testing.T) {
+	ctx := context.Background()
+	local := NewMemoryCache(10)
+	remote := NewMemoryCache(10)
+	tiered, err := NewTieredCache(local, remote, time.Minute, noop.NewMeterProvider().Meter("test"))
+	require.NoError(t, err)
+
+	require.NoError(t, local.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, remote.Set(ctx, "b", []byte("2"), 0))
+
+	values := tiered.GetMulti(ctx, []string{"a", "b", "c"})
+	assert.Equal(t, map[string][]byte{"a": []byte("1"), "b": []byte("2")}, values)
+
+	_, ok := local.Get(ctx, "b")
+	assert.True(t, ok)
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// rateLimitKeyPrefix separates token buckets from cached configurations
+const rateLimitKeyPrefix = "ratelimit:"
+
+// tokenBucketScript takes a token from the bucket refilled at ARGV[1] tokens per second
+// up to ARGV[2] tokens. It returns whether a token was taken and, if not, how many
+// milliseconds until the next one. Redis time is used, so replicas need no synchronized clocks.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = time[1] * 1000 + math.floor(time[2] / 1000)
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, wait}
+`)
+
+// TakeToken takes a token from the bucket of the key shared by all instances.
+// The bucket is refilled at rate tokens per second and holds at most burst tokens.
+// When no token is left it returns how long to wait for the next one.
+func (r *RedisCache) TakeToken(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	var result []int64
+	err := r.call(ctx, r.writeTimeout, func(ctx context.Context) error {
+		var err error
+		result, err = tokenBucketScript.Run(ctx, r.client, []string{rateLimitKeyPrefix + key},
+			strconv.FormatFloat(rate, 'f', -1, 64), burst).Int64Slice()
+		return err
+	})
+	if err != nil {
+		return false, 0, err
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
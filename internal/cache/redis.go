@@ -2,63 +2,269 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrUnavailable is returned when the cache is not reachable and calls are short-circuited
+var ErrUnavailable = errors.New("cache is unavailable")
+
 // RedisCache implements cache.Interface using Redis
 type RedisCache struct {
-	client *redis.Client
-	ctx    context.Context
+	client       *redis.Client
+	readTimeout  time.Duration // zero means no limit besides the caller's context
+	writeTimeout time.Duration
+
+	// Set for resilient connections only
+	breaker       *circuitBreaker
+	logger        *slog.Logger
+	checkInterval time.Duration
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
 }
 
-// NewRedisCache creates a new Redis cache instance
-func NewRedisCache(addr, password string, db int) (*RedisCache, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
-	})
+// RedisOptions configures a resilient Redis connection
+type RedisOptions struct {
+	Addr     string
+	Password string
+	DB       int
 
-	ctx := context.Background()
+	ReadTimeout      time.Duration // Deadline of a single read call
+	WriteTimeout     time.Duration // Deadline of a single write call
+	FailureThreshold int           // Consecutive failures that make the cache unavailable
+	CheckInterval    time.Duration // How often an unavailable Redis is checked for recovery
+}
+
+// NewRedisCache creates a new Redis cache instance. Options are used as is, so TLS,
+// the ACL username and timeouts parsed from a rediss:// or redis:// URL are kept.
+func NewRedisCache(options *redis.Options) (*RedisCache, error) {
+	client := redis.NewClient(options)
 
 	// Test connection
-	if err := client.Ping(ctx).Err(); err != nil {
+	if err := client.Ping(context.Background()).Err(); err != nil {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
 	return &RedisCache{
 		client: client,
-		ctx:    ctx,
 	}, nil
 }
 
+// NewResilientRedisCache creates a Redis cache that works while Redis is unavailable.
+// After FailureThreshold consecutive failures calls are short-circuited: reads are misses
+// and writes return ErrUnavailable. Redis is pinged in the background until it recovers.
+func NewResilientRedisCache(options RedisOptions, logger *slog.Logger) *RedisCache {
+	client := redis.NewClient(&redis.Options{
+		Addr:       options.Addr,
+		Password:   options.Password,
+		DB:         options.DB,
+		MaxRetries: -1, // Fail fast, the request is served without cache
+		// Deadlines are taken from the context of each call
+		ContextTimeoutEnabled: true,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &RedisCache{
+		client:        client,
+		readTimeout:   options.ReadTimeout,
+		writeTimeout:  options.WriteTimeout,
+		breaker:       newCircuitBreaker(options.FailureThreshold),
+		logger:        logger,
+		checkInterval: options.CheckInterval,
+		cancel:        cancel,
+	}
+
+	// Start unavailable if Redis cannot be reached, the health check restores it later
+	if err := r.Ping(ctx); err != nil {
+		r.breaker.open = true
+		logger.Warn("redis is unavailable, starting without cache", "addr", options.Addr, "error", err.Error())
+	}
+
+	r.wg.Add(1)
+	go r.checkHealth(ctx)
+
+	return r
+}
+
+// Healthy reports whether Redis calls are currently allowed
+func (r *RedisCache) Healthy() bool {
+	return r.breaker == nil || r.breaker.allow()
+}
+
 // Get retrieves a value from Redis cache
-func (r *RedisCache) Get(key string) ([]byte, bool) {
-	val, err := r.client.Get(r.ctx, key).Result()
+func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	var val string
+	err := r.call(ctx, r.readTimeout, func(ctx context.Context) error {
+		var err error
+		val, err = r.client.Get(ctx, key).Result()
+		return err
+	})
 	if err != nil {
-		if err == redis.Nil {
-			return nil, false // Key doesn't exist
-		}
-		return nil, false // Other error
+		r.logReadError(ctx, err)
+		return nil, false // Key doesn't exist or Redis is not available
 	}
 
 	return []byte(val), true
 }
 
+// GetMulti retrieves several values from Redis cache with a single MGET, missing keys are omitted
+func (r *RedisCache) GetMulti(ctx context.Context, keys []string) map[string][]byte {
+	values := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return values
+	}
+
+	var results []any
+	err := r.call(ctx, r.readTimeout, func(ctx context.Context) error {
+		var err error
+		results, err = r.client.MGet(ctx, keys...).Result()
+		return err
+	})
+	if err != nil {
+		r.logReadError(ctx, err)
+		return values
+	}
+
+	for i, result := range results {
+		if val, ok := result.(string); ok {
+			values[keys[i]] = []byte(val)
+		}
+	}
+	return values
+}
+
 // Set stores a value in Redis cache with TTL
-func (r *RedisCache) Set(key string, value []byte, ttl time.Duration) error {
-	return r.client.Set(r.ctx, key, value, ttl).Err()
+func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.call(ctx, r.writeTimeout, func(ctx context.Context) error {
+		return r.client.Set(ctx, key, value, ttl).Err()
+	})
 }
 
 // Delete removes a key from Redis cache
-func (r *RedisCache) Delete(key string) error {
-	return r.client.Del(r.ctx, key).Err()
+func (r *RedisCache) Delete(ctx context.Context, key string) error {
+	return r.call(ctx, r.writeTimeout, func(ctx context.Context) error {
+		return r.client.Del(ctx, key).Err()
+	})
+}
+
+// deleteBatchSize is the number of keys scanned and deleted per round-trip
+const deleteBatchSize = 500
+
+// DeletePrefix removes all keys starting with prefix from Redis cache.
+// The scan may take many round-trips, so only the deadline of ctx applies.
+func (r *RedisCache) DeletePrefix(ctx context.Context, prefix string) error {
+	return r.call(ctx, 0, func(ctx context.Context) error {
+		iter := r.client.Scan(ctx, 0, prefix+"*", deleteBatchSize).Iterator()
+
+		keys := make([]string, 0, deleteBatchSize)
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+			if len(keys) == deleteBatchSize {
+				if err := r.client.Unlink(ctx, keys...).Err(); err != nil {
+					return err
+				}
+				keys = keys[:0]
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+
+		if len(keys) > 0 {
+			return r.client.Unlink(ctx, keys...).Err()
+		}
+		return nil
+	})
 }
 
 // Close closes the Redis connection
 func (r *RedisCache) Close() error {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
 	return r.client.Close()
 }
+
+// call runs a Redis command with the timeout through the circuit breaker.
+// Zero timeout leaves only the deadline of ctx.
+func (r *RedisCache) call(ctx context.Context, timeout time.Duration, command func(ctx context.Context) error) error {
+	if r.breaker != nil && !r.breaker.allow() {
+		return ErrUnavailable
+	}
+
+	callCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := command(callCtx)
+	if r.breaker == nil {
+		return err
+	}
+
+	if err == nil || errors.Is(err, redis.Nil) {
+		r.breaker.success()
+		return err
+	}
+
+	// Calls abandoned by the caller say nothing about Redis health
+	if ctx.Err() != nil {
+		return err
+	}
+
+	if r.breaker.failure() {
+		r.logger.Error("redis is unavailable, serving without cache", "error", err.Error())
+	}
+	return err
+}
+
+// Ping checks the connection bypassing the circuit breaker
+func (r *RedisCache) Ping(ctx context.Context) error {
+	if r.readTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.readTimeout)
+		defer cancel()
+	}
+	return r.client.Ping(ctx).Err()
+}
+
+// logReadError logs failed reads except misses, short-circuited calls and calls abandoned by the caller
+func (r *RedisCache) logReadError(ctx context.Context, err error) {
+	if r.logger == nil || errors.Is(err, redis.Nil) || errors.Is(err, ErrUnavailable) || ctx.Err() != nil {
+		return
+	}
+	r.logger.Warn("failed to read from redis", "error", err.Error())
+}
+
+// checkHealth pings unavailable Redis until it recovers
+func (r *RedisCache) checkHealth(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.breaker.allow() {
+				continue
+			}
+			if err := r.Ping(ctx); err != nil {
+				continue
+			}
+			if r.breaker.close() {
+				r.logger.Info("redis connection restored")
+			}
+		}
+	}
+}
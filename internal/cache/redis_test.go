@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	breaker := newCircuitBreaker(2)
+
+	assert.False(t, breaker.failure())
+	breaker.success()
+	assert.False(t, breaker.failure())
+	assert.True(t, breaker.allow())
+
+	assert.True(t, breaker.failure())
+	assert.False(t, breaker.allow())
+	assert.False(t, breaker.failure(), "already open breaker is not reported again")
+
+	assert.True(t, breaker.close())
+	assert.True(t, breaker.allow())
+}
+
+func TestResilientRedisCache_StartsWithoutRedis(t *testing.T) {
+	ctx := context.Background()
+	redisCache := NewResilientRedisCache(RedisOptions{
+		Addr:             "127.0.0.1:1",
+		ReadTimeout:      100 * time.Millisecond,
+		WriteTimeout:     100 * time.Millisecond,
+		FailureThreshold: 1,
+		CheckInterval:    time.Hour,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer redisCache.Close() // nolint:errcheck
+
+	assert.False(t, redisCache.Healthy())
+
+	_, ok := redisCache.Get(ctx, "key")
+	assert.False(t, ok)
+	assert.Empty(t, redisCache.GetMulti(ctx, []string{"a", "b"}))
+	assert.ErrorIs(t, redisCache.Set(ctx, "key", []byte("value"), time.Minute), ErrUnavailable)
+	assert.ErrorIs(t, redisCache.DeletePrefix(ctx, "config:"), ErrUnavailable)
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Cache tiers reported in metrics
+const (
+	TierLocal  = "local"
+	TierRemote = "remote"
+)
+
+// TieredCache implements cache.Interface with a short-lived local cache in front of a shared remote cache
+type TieredCache struct {
+	local    Interface
+	remote   Interface
+	localTTL time.Duration
+	hits     metric.Int64Counter
+	misses   metric.Int64Counter
+}
+
+// NewTieredCache creates a cache that reads from local first and falls back to remote.
+// Values are kept locally for at most localTTL so that changes in remote become visible quickly.
+func NewTieredCache(local, remote Interface, localTTL time.Duration, meter metric.Meter) (*TieredCache, error) {
+	hits, err := meter.Int64Counter(
+		"cache.hits",
+		metric.WithDescription("Number of cache hits by tier"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	misses, err := meter.Int64Counter(
+		"cache.misses",
+		metric.WithDescription("Number of cache misses by tier"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TieredCache{
+		local:    local,
+		remote:   remote,
+		localTTL: localTTL,
+		hits:     hits,
+		misses:   misses,
+	}, nil
+}
+
+// Get retrieves a value from the local cache or, on a local miss, from the remote one
+func (t *TieredCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	if value, ok := t.local.Get(ctx, key); ok {
+		t.record(ctx, t.hits, TierLocal)
+		return value, true
+	}
+	t.record(ctx, t.misses, TierLocal)
+
+	value, ok := t.remote.Get(ctx, key)
+	if !ok {
+		t.record(ctx, t.misses, TierRemote)
+		return nil, false
+	}
+	t.record(ctx, t.hits, TierRemote)
+
+	// Local cache errors are not critical, the value is served from remote next time
+	_ = t.local.Set(ctx, key, value, t.localTTL)
+	return value, true
+}
+
+// GetMulti retrieves values from the local cache and the missing ones from the remote cache
+func (t *TieredCache) GetMulti(ctx context.Context, keys []string) map[string][]byte {
+	values := t.local.GetMulti(ctx, keys)
+
+	missing := make([]string, 0, len(keys)-len(values))
+	for _, key := range keys {
+		if _, ok := values[key]; ok {
+			t.record(ctx, t.hits, TierLocal)
+			continue
+		}
+		t.record(ctx, t.misses, TierLocal)
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 {
+		return values
+	}
+
+	remote := t.remote.GetMulti(ctx, missing)
+	for _, key := range missing {
+		value, ok := remote[key]
+		if !ok {
+			t.record(ctx, t.misses, TierRemote)
+			continue
+		}
+		t.record(ctx, t.hits, TierRemote)
+
+		_ = t.local.Set(ctx, key, value, t.localTTL)
+		values[key] = value
+	}
+	return values
+}
+
+// Set stores a value in both tiers, the local copy lives no longer than localTTL
+func (t *TieredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	localTTL := t.localTTL
+	if ttl > 0 && ttl < localTTL {
+		localTTL = ttl
+	}
+	_ = t.local.Set(ctx, key, value, localTTL)
+
+	return t.remote.Set(ctx, key, value, ttl)
+}
+
+// Delete removes a key from both tiers
+func (t *TieredCache) Delete(ctx context.Context, key string) error {
+	return errors.Join(t.local.Delete(ctx, key), t.remote.Delete(ctx, key))
+}
+
+// DeletePrefix removes all keys starting with prefix from both tiers
+func (t *TieredCache) DeletePrefix(ctx context.Context, prefix string) error {
+	return errors.Join(t.local.DeletePrefix(ctx, prefix), t.remote.DeletePrefix(ctx, prefix))
+}
+
+// Close closes both tiers
+func (t *TieredCache) Close() error {
+	return errors.Join(t.local.Close(), t.remote.Close())
+}
+
+// record increments the counter for the tier
+func (t *TieredCache) record(ctx context.Context, counter metric.Int64Counter, tier string) {
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("tier", tier)))
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"sw-config-api/internal/app"
+	"sw-config-api/internal/storage"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrUsage is returned when the command line arguments are invalid
+var ErrUsage = errors.New("invalid usage")
+
+const usage = `Usage: sw-config-cli <command> [flags]
+
+Commands:
+  import    import configuration data from JSON files into the database
+  export    export configuration data from the database into JSON files
+  diff      compare configuration data of two databases or exported snapshots
+  promote   apply configuration data of one environment to another database
+
+Database connection is configured with the same environment variables as the API
+(DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME). After applying changes import publishes
+a cache invalidation to Redis configured by REDIS_ADDR, REDIS_PASSWORD and REDIS_DB.
+
+Run 'sw-config-cli <command> -h' for command flags.
+`
+
+// Run executes the command given by command line arguments
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage) // nolint:errcheck
+		return ErrUsage
+	}
+
+	switch args[0] {
+	case "import":
+		return runImport(ctx, args[1:], stdout, stderr)
+	case "export":
+		return runExport(ctx, args[1:], stdout, stderr)
+	case "diff":
+		return runDiff(ctx, args[1:], stdout, stderr)
+	case "promote":
+		return runPromote(ctx, args[1:], stdin, stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage) // nolint:errcheck
+		return nil
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage) // nolint:errcheck
+		return ErrUsage
+	}
+}
+
+// newFlagSet creates a flag set that reports errors instead of exiting
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+// parseFlags parses command flags and maps parsing failures to ErrUsage
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return ErrUsage
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(flags.Output(), "unexpected arguments: %v\n", flags.Args()) // nolint:errcheck
+		return ErrUsage
+	}
+	return nil
+}
+
+// openDatabase connects to the database configured by environment variables
+func openDatabase(ctx context.Context) (*sqlx.DB, *app.Config, error) {
+	config, err := app.LoadConfig(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	db, err := storage.New(config.StorageConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, config, nil
+}
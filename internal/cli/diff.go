@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sw-config-api/internal/storage"
+)
+
+// Diff output formats
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// validateOutput checks that the diff output format is supported
+func validateOutput(format string) error {
+	if format != outputText && format != outputJSON {
+		return fmt.Errorf("%w: unsupported output format %q", ErrUsage, format)
+	}
+	return nil
+}
+
+// writeDiff writes the diff in the requested output format
+func writeDiff(w io.Writer, diff *storage.DatasetDiff, format string) error {
+	switch format {
+	case outputText:
+		return printDiff(w, diff)
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	default:
+		return validateOutput(format)
+	}
+}
+
+// printDiff writes a human readable representation of the diff
+func printDiff(w io.Writer, diff *storage.DatasetDiff) error {
+	out := bufio.NewWriter(w)
+
+	if diff.IsEmpty() {
+		fmt.Fprintln(out, "no changes") // nolint:errcheck
+		return out.Flush()
+	}
+
+	printResourceDiff(out, storage.AssetsTable, diff.Assets)
+	printResourceDiff(out, storage.DefinitionsTable, diff.Definitions)
+	printURLDiff(out, storage.AssetURLsTable, diff.AssetURLs)
+	printURLDiff(out, storage.DefinitionURLsTable, diff.DefinitionURLs)
+	printPlatformVersionDiff(out, diff.PlatformVersions)
+	printEntryPointDiff(out, diff.EntryPoints)
+
+	return out.Flush()
+}
+
+func printResourceDiff(out io.Writer, title string, diff storage.ResourceDiff) {
+	if diff.IsEmpty() {
+		return
+	}
+
+	fmt.Fprintf(out, "%s:\n", title) // nolint:errcheck
+	for _, resource := range diff.Added {
+		fmt.Fprintf(out, "  + %s %s %s\n", resource.Platform, resource.Version, resource.Hash) // nolint:errcheck
+	}
+	for _, change := range diff.Changed {
+		fmt.Fprintf(out, "  ~ %s %s %s -> %s\n", change.Platform, change.Version, change.OldHash, change.NewHash) // nolint:errcheck
+	}
+	for _, resource := range diff.Removed {
+		fmt.Fprintf(out, "  - %s %s %s\n", resource.Platform, resource.Version, resource.Hash) // nolint:errcheck
+	}
+}
+
+func printURLDiff(out io.Writer, title string, diff storage.URLDiff) {
+	if diff.IsEmpty() {
+		return
+	}
+
+	fmt.Fprintf(out, "%s:\n", title) // nolint:errcheck
+	for _, url := range diff.Added {
+		fmt.Fprintf(out, "  + %s\n", url) // nolint:errcheck
+	}
+	for _, url := range diff.Removed {
+		fmt.Fprintf(out, "  - %s\n", url) // nolint:errcheck
+	}
+}
+
+func printPlatformVersionDiff(out io.Writer, diff storage.PlatformVersionDiff) {
+	if diff.IsEmpty() {
+		return
+	}
+
+	fmt.Fprintf(out, "%s:\n", storage.PlatformVersionsTable) // nolint:errcheck
+	for _, value := range diff.Added {
+		fmt.Fprintf(out, "  + %s required=%s store=%s\n", value.Platform, value.RequiredVersion, value.StoreVersion) // nolint:errcheck
+	}
+	for _, change := range diff.Changed {
+		fmt.Fprintf(out, "  ~ %s required=%s store=%s -> required=%s store=%s\n", change.Platform, // nolint:errcheck
+			change.Old.RequiredVersion, change.Old.StoreVersion, change.New.RequiredVersion, change.New.StoreVersion)
+	}
+	for _, value := range diff.Removed {
+		fmt.Fprintf(out, "  - %s required=%s store=%s\n", value.Platform, value.RequiredVersion, value.StoreVersion) // nolint:errcheck
+	}
+}
+
+func printEntryPointDiff(out io.Writer, diff storage.EntryPointDiff) {
+	if diff.IsEmpty() {
+		return
+	}
+
+	fmt.Fprintf(out, "%s:\n", storage.EntryPointsTable) // nolint:errcheck
+	for _, value := range diff.Added {
+		fmt.Fprintf(out, "  + %s %s\n", value.Key, value.URL) // nolint:errcheck
+	}
+	for _, change := range diff.Changed {
+		fmt.Fprintf(out, "  ~ %s %s -> %s\n", change.Key, change.OldURL, change.NewURL) // nolint:errcheck
+	}
+	for _, value := range diff.Removed {
+		fmt.Fprintf(out, "  - %s %s\n", value.Key, value.URL) // nolint:errcheck
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"sw-config-api/internal/cache"
+	"sw-config-api/internal/storage"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// notifyInvalidation publishes the change to API instances subscribed to Redis.
+// The data is already applied at this point, so failures are reported as warnings
+// and cached entries expire by TTL.
+func notifyInvalidation(ctx context.Context, options *redis.Options, diff *storage.DatasetDiff, stderr io.Writer) {
+	redisCache, err := cache.NewRedisCache(options)
+	if err != nil {
+		fmt.Fprintf(stderr, "warning: cache invalidation is not published: %v\n", err) // nolint:errcheck
+		return
+	}
+	defer redisCache.Close() // nolint:errcheck
+
+	if err := redisCache.PublishInvalidation(ctx, cache.InvalidationFor(diff)); err != nil {
+		fmt.Fprintf(stderr, "warning: cache invalidation is not published: %v\n", err) // nolint:errcheck
+		return
+	}
+	fmt.Fprintln(stderr, "cache invalidation published") // nolint:errcheck
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"sw-config-api/internal/storage"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// runImport imports configuration data from JSON files into the database
+func runImport(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("import", stderr)
+	dir := flags.String("dir", ".", "directory with assets.json, definitions.json and *_urls.json files")
+	dryRun := flags.Bool("dry-run", false, "print the changes without applying them")
+	prune := flags.Bool("prune", false, "delete data that is missing in the files")
+	notify := flags.Bool("notify", true, "publish cache invalidation to Redis after import")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	dataset, err := storage.ReadDatasetDir(*dir)
+	if err != nil {
+		return err
+	}
+	if err := dataset.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration data:\n%w", err)
+	}
+
+	db, config, err := openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close() // nolint:errcheck
+
+	repository := storage.NewDatasetRepository(db)
+	current, err := repository.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	diff := storage.DiffDatasets(current, dataset)
+	if !*prune {
+		diff = diff.WithoutRemovals()
+	}
+
+	if err := printDiff(stdout, diff); err != nil {
+		return err
+	}
+
+	if *dryRun || diff.IsEmpty() {
+		return nil
+	}
+
+	if err := repository.Apply(ctx, diff); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "import completed") // nolint:errcheck
+
+	if *notify {
+		notifyInvalidation(ctx, &redis.Options{
+			Addr:     config.RedisAddr,
+			Password: config.RedisPassword,
+			DB:       config.RedisDB,
+		}, diff, stderr)
+	}
+	return nil
+}
+
+// runExport exports configuration data from the database into JSON files
+func runExport(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := newFlagSet("export", stderr)
+	dir := flags.String("dir", ".", "directory to write assets.json, definitions.json and *_urls.json files to")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	db, _, err := openDatabase(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close() // nolint:errcheck
+
+	dataset, err := storage.NewDatasetRepository(db).Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := storage.WriteDatasetDir(*dir, dataset); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "exported to %s\n", *dir) // nolint:errcheck
+	return nil
+}
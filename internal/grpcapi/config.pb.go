@@ -0,0 +1,474 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: config.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetConfigRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Client application version (SemVer format MAJOR.MINOR.PATCH)
+	AppVersion string `protobuf:"bytes,1,opt,name=app_version,json=appVersion,proto3" json:"app_version,omitempty"`
+	// Client platform (e.g., android, ios)
+	Platform string `protobuf:"bytes,2,opt,name=platform,proto3" json:"platform,omitempty"`
+	// Specific assets version (SemVer format MAJOR.MINOR.PATCH). If not set, uses app_version.
+	AssetsVersion string `protobuf:"bytes,3,opt,name=assets_version,json=assetsVersion,proto3" json:"assets_version,omitempty"`
+	// Specific definitions version (SemVer format MAJOR.MINOR.PATCH). If not set, uses app_version.
+	DefinitionsVersion string `protobuf:"bytes,4,opt,name=definitions_version,json=definitionsVersion,proto3" json:"definitions_version,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetConfigRequest) Reset() {
+	*x = GetConfigRequest{}
+	mi := &file_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigRequest) ProtoMessage() {}
+
+func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetConfigRequest) GetAppVersion() string {
+	if x != nil {
+		return x.AppVersion
+	}
+	return ""
+}
+
+func (x *GetConfigRequest) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *GetConfigRequest) GetAssetsVersion() string {
+	if x != nil {
+		return x.AssetsVersion
+	}
+	return ""
+}
+
+func (x *GetConfigRequest) GetDefinitionsVersion() string {
+	if x != nil {
+		return x.DefinitionsVersion
+	}
+	return ""
+}
+
+type GetConfigResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Config *Config                `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// Set when the configuration is served from cache after its freshness period,
+	// e.g. while it is being refreshed or when the configuration storage is unavailable.
+	Stale         bool `protobuf:"varint,2,opt,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigResponse) Reset() {
+	*x = GetConfigResponse{}
+	mi := &file_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigResponse) ProtoMessage() {}
+
+func (x *GetConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetConfigResponse) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetConfigResponse) GetConfig() *Config {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *GetConfigResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+type Config struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Version           *Version               `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	BackendEntryPoint *BackendService        `protobuf:"bytes,2,opt,name=backend_entry_point,json=backendEntryPoint,proto3" json:"backend_entry_point,omitempty"`
+	Assets            *Resource              `protobuf:"bytes,3,opt,name=assets,proto3" json:"assets,omitempty"`
+	Definitions       *Resource              `protobuf:"bytes,4,opt,name=definitions,proto3" json:"definitions,omitempty"`
+	Notifications     *BackendService        `protobuf:"bytes,5,opt,name=notifications,proto3" json:"notifications,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_config_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Config) GetVersion() *Version {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *Config) GetBackendEntryPoint() *BackendService {
+	if x != nil {
+		return x.BackendEntryPoint
+	}
+	return nil
+}
+
+func (x *Config) GetAssets() *Resource {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *Config) GetDefinitions() *Resource {
+	if x != nil {
+		return x.Definitions
+	}
+	return nil
+}
+
+func (x *Config) GetNotifications() *BackendService {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+type Version struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Required      string                 `protobuf:"bytes,1,opt,name=required,proto3" json:"required,omitempty"`
+	Store         string                 `protobuf:"bytes,2,opt,name=store,proto3" json:"store,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	mi := &file_config_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Version) GetRequired() string {
+	if x != nil {
+		return x.Required
+	}
+	return ""
+}
+
+func (x *Version) GetStore() string {
+	if x != nil {
+		return x.Store
+	}
+	return ""
+}
+
+type Resource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Hash          string                 `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Urls          []string               `protobuf:"bytes,3,rep,name=urls,proto3" json:"urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resource) Reset() {
+	*x = Resource{}
+	mi := &file_config_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resource) ProtoMessage() {}
+
+func (x *Resource) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resource.ProtoReflect.Descriptor instead.
+func (*Resource) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Resource) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Resource) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Resource) GetUrls() []string {
+	if x != nil {
+		return x.Urls
+	}
+	return nil
+}
+
+type BackendService struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JsonrpcUrl    string                 `protobuf:"bytes,1,opt,name=jsonrpc_url,json=jsonrpcUrl,proto3" json:"jsonrpc_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackendService) Reset() {
+	*x = BackendService{}
+	mi := &file_config_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackendService) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendService) ProtoMessage() {}
+
+func (x *BackendService) ProtoReflect() protoreflect.Message {
+	mi := &file_config_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendService.ProtoReflect.Descriptor instead.
+func (*BackendService) Descriptor() ([]byte, []int) {
+	return file_config_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BackendService) GetJsonrpcUrl() string {
+	if x != nil {
+		return x.JsonrpcUrl
+	}
+	return ""
+}
+
+var File_config_proto protoreflect.FileDescriptor
+
+const file_config_proto_rawDesc = "" +
+	"\n" +
+	"\fconfig.proto\x12\vswconfig.v1\"\xa7\x01\n" +
+	"\x10GetConfigRequest\x12\x1f\n" +
+	"\vapp_version\x18\x01 \x01(\tR\n" +
+	"appVersion\x12\x1a\n" +
+	"\bplatform\x18\x02 \x01(\tR\bplatform\x12%\n" +
+	"\x0eassets_version\x18\x03 \x01(\tR\rassetsVersion\x12/\n" +
+	"\x13definitions_version\x18\x04 \x01(\tR\x12definitionsVersion\"V\n" +
+	"\x11GetConfigResponse\x12+\n" +
+	"\x06config\x18\x01 \x01(\v2\x13.swconfig.v1.ConfigR\x06config\x12\x14\n" +
+	"\x05stale\x18\x02 \x01(\bR\x05stale\"\xb0\x02\n" +
+	"\x06Config\x12.\n" +
+	"\aversion\x18\x01 \x01(\v2\x14.swconfig.v1.VersionR\aversion\x12K\n" +
+	"\x13backend_entry_point\x18\x02 \x01(\v2\x1b.swconfig.v1.BackendServiceR\x11backendEntryPoint\x12-\n" +
+	"\x06assets\x18\x03 \x01(\v2\x15.swconfig.v1.ResourceR\x06assets\x127\n" +
+	"\vdefinitions\x18\x04 \x01(\v2\x15.swconfig.v1.ResourceR\vdefinitions\x12A\n" +
+	"\rnotifications\x18\x05 \x01(\v2\x1b.swconfig.v1.BackendServiceR\rnotifications\";\n" +
+	"\aVersion\x12\x1a\n" +
+	"\brequired\x18\x01 \x01(\tR\brequired\x12\x14\n" +
+	"\x05store\x18\x02 \x01(\tR\x05store\"L\n" +
+	"\bResource\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x12\n" +
+	"\x04hash\x18\x02 \x01(\tR\x04hash\x12\x12\n" +
+	"\x04urls\x18\x03 \x03(\tR\x04urls\"1\n" +
+	"\x0eBackendService\x12\x1f\n" +
+	"\vjsonrpc_url\x18\x01 \x01(\tR\n" +
+	"jsonrpcUrl2[\n" +
+	"\rConfigService\x12J\n" +
+	"\tGetConfig\x12\x1d.swconfig.v1.GetConfigRequest\x1a\x1e.swconfig.v1.GetConfigResponseB(Z&sw-config-api/internal/grpcapi;grpcapib\x06proto3"
+
+var (
+	file_config_proto_rawDescOnce sync.Once
+	file_config_proto_rawDescData []byte
+)
+
+func file_config_proto_rawDescGZIP() []byte {
+	file_config_proto_rawDescOnce.Do(func() {
+		file_config_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_config_proto_rawDesc), len(file_config_proto_rawDesc)))
+	})
+	return file_config_proto_rawDescData
+}
+
+var file_config_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_config_proto_goTypes = []any{
+	(*GetConfigRequest)(nil),  // 0: swconfig.v1.GetConfigRequest
+	(*GetConfigResponse)(nil), // 1: swconfig.v1.GetConfigResponse
+	(*Config)(nil),            // 2: swconfig.v1.Config
+	(*Version)(nil),           // 3: swconfig.v1.Version
+	(*Resource)(nil),          // 4: swconfig.v1.Resource
+	(*BackendService)(nil),    // 5: swconfig.v1.BackendService
+}
+var file_config_proto_depIdxs = []int32{
+	2, // 0: swconfig.v1.GetConfigResponse.config:type_name -> swconfig.v1.Config
+	3, // 1: swconfig.v1.Config.version:type_name -> swconfig.v1.Version
+	5, // 2: swconfig.v1.Config.backend_entry_point:type_name -> swconfig.v1.BackendService
+	4, // 3: swconfig.v1.Config.assets:type_name -> swconfig.v1.Resource
+	4, // 4: swconfig.v1.Config.definitions:type_name -> swconfig.v1.Resource
+	5, // 5: swconfig.v1.Config.notifications:type_name -> swconfig.v1.BackendService
+	0, // 6: swconfig.v1.ConfigService.GetConfig:input_type -> swconfig.v1.GetConfigRequest
+	1, // 7: swconfig.v1.ConfigService.GetConfig:output_type -> swconfig.v1.GetConfigResponse
+	7, // [7:8] is the sub-list for method output_type
+	6, // [6:7] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_config_proto_init() }
+func file_config_proto_init() {
+	if File_config_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_config_proto_rawDesc), len(file_config_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_config_proto_goTypes,
+		DependencyIndexes: file_config_proto_depIdxs,
+		MessageInfos:      file_config_proto_msgTypes,
+	}.Build()
+	File_config_proto = out.File
+	file_config_proto_goTypes = nil
+	file_config_proto_depIdxs = nil
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// levelRequest is the body of the level endpoint
+type levelRequest struct {
+	Level string `json:"level"`
+}
+
+// LevelHandler reports the log level on GET and changes it on PUT with {"level": "debug"}
+func LevelHandler(level *slog.LevelVar, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var request levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				writeError(w, "invalid request body")
+				return
+			}
+
+			var newLevel slog.Level
+			if err := newLevel.UnmarshalText([]byte(request.Level)); err != nil {
+				writeError(w, "invalid log level: "+request.Level)
+				return
+			}
+
+			previous := level.Level()
+			level.Set(newLevel)
+			logger.Warn("log level changed", "from", previous.String(), "to", newLevel.String())
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(levelRequest{Level: level.Level().String()})
+	})
+}
+
+func writeError(w http.ResponseWriter, message string) {
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
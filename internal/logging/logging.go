@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Log formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Standard destinations, any other output is a file path
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// Config selects how logs are written
+type Config struct {
+	Level  string // debug, info, warn or error
+	Format string // json or text
+	Output string // stdout, stderr or a file path
+}
+
+// Logger is a logger with a level that can be changed at runtime
+type Logger struct {
+	*slog.Logger
+	Level *slog.LevelVar
+	out   io.Closer // nil for standard streams
+}
+
+// New creates a logger writing to the configured output
+func New(config Config) (*Logger, error) {
+	level := new(slog.LevelVar)
+	if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", config.Level, err)
+	}
+
+	var (
+		w   io.Writer
+		out io.Closer
+	)
+	switch config.Output {
+	case OutputStdout, "":
+		w = os.Stdout
+	case OutputStderr:
+		w = os.Stderr
+	default:
+		file, err := os.OpenFile(config.Output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		w, out = file, file
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(config.Format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		if out != nil {
+			_ = out.Close()
+		}
+		return nil, fmt.Errorf("unsupported log format: %s", config.Format)
+	}
+
+	return &Logger{Logger: slog.New(handler), Level: level, out: out}, nil
+}
+
+// Close closes the log file, logs written to standard streams need no closing
+func (l *Logger) Close() error {
+	if l.out == nil {
+		return nil
+	}
+	return l.out.Close()
+}
+
+// contextKey is a custom type for context keys to avoid collisions
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the logger
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger carried by ctx. Outside of requests,
+// e.g. in background jobs, it returns fallback.
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return fallback
+}
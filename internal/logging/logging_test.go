@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.log")
+
+	logger, err := New(Config{Level: "warn", Format: FormatText, Output: path})
+	require.NoError(t, err)
+
+	logger.Info("skipped")
+	logger.Warn("written")
+	require.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "skipped")
+	assert.Contains(t, string(data), "msg=written")
+
+	_, err = New(Config{Level: "verbose", Format: FormatJSON})
+	assert.Error(t, err)
+	_, err = New(Config{Level: "info", Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestFromContext(t *testing.T) {
+	fallback := slog.New(slog.NewTextHandler(io.Discard, nil))
+	requestLogger := fallback.With("request_id", "42")
+
+	assert.Same(t, fallback, FromContext(context.Background(), fallback))
+	assert.Same(t, requestLogger, FromContext(NewContext(context.Background(), requestLogger), fallback))
+}
+
+func TestLevelHandler(t *testing.T) {
+	level := new(slog.LevelVar)
+	handler := LevelHandler(level, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, "/log-level", strings.NewReader(body)))
+		return recorder
+	}
+
+	recorder := serve(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"level": "INFO"}`, recorder.Body.String())
+
+	recorder = serve(http.MethodPut, `{"level": "debug"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"level": "DEBUG"}`, recorder.Body.String())
+	assert.Equal(t, slog.LevelDebug, level.Level())
+
+	recorder = serve(http.MethodPut, `{"level": "verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, slog.LevelDebug, level.Level())
+
+	recorder = serve(http.MethodPost, `{"level": "info"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
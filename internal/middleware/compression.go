@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content codings in order of preference
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+var (
+	gzipWriters = sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	}}
+	brotliWriters = sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+	}}
+)
+
+// resettableWriter is a pooled compressor
+type resettableWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// Compress compresses responses of at least minSize bytes with brotli or gzip, whichever
+// the client accepts, preferring brotli. Responses are buffered to decide on their size,
+// configurations are small enough for that.
+func Compress(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			buffer := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(buffer, r)
+			buffer.flush(negotiateEncoding(r.Header.Get("Accept-Encoding")), minSize)
+		})
+	}
+}
+
+// negotiateEncoding picks a supported coding from Accept-Encoding, codings with q=0 are refused
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+
+		quality := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+		accepted[coding] = quality > 0
+	}
+
+	for _, encoding := range []string{encodingBrotli, encodingGzip} {
+		if allowed, ok := accepted[encoding]; ok {
+			if allowed {
+				return encoding
+			}
+			continue
+		}
+		if accepted["*"] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// bufferedResponse holds the response until it is complete
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+// flush writes the response compressed if the client accepts the encoding and
+// the response is large enough and not encoded already
+func (b *bufferedResponse) flush(encoding string, minSize int) {
+	// Caches must keep compressed and plain responses apart. Vary is added after the
+	// handler, ogen replaces the header when the response sets it.
+	header := b.ResponseWriter.Header()
+	header.Add("Vary", "Accept-Encoding")
+
+	if encoding == "" || b.body.Len() < minSize || header.Get("Content-Encoding") != "" {
+		b.ResponseWriter.WriteHeader(b.status)
+		_, _ = b.ResponseWriter.Write(b.body.Bytes())
+		return
+	}
+
+	header.Set("Content-Encoding", encoding)
+	header.Del("Content-Length")
+	b.ResponseWriter.WriteHeader(b.status)
+
+	pool := &gzipWriters
+	if encoding == encodingBrotli {
+		pool = &brotliWriters
+	}
+	compressor := pool.Get().(resettableWriter)
+	defer pool.Put(compressor)
+
+	compressor.Reset(b.ResponseWriter)
+	_, _ = compressor.Write(b.body.Bytes())
+	_ = compressor.Close()
+}
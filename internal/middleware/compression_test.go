@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", encodingGzip},
+		{"gzip, deflate, br", encodingBrotli},
+		{"br;q=0, gzip;q=0.5", encodingGzip},
+		{"BR", encodingBrotli},
+		{"*", encodingBrotli},
+		{"*, br;q=0", encodingGzip},
+		{"gzip;q=0", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.encoding, negotiateEncoding(tt.acceptEncoding), tt.acceptEncoding)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	body := strings.Repeat(`{"urls": ["a.cdn.application.com"]}`, 100)
+	handler := Compress(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "X-Device-ID")
+		if r.URL.Query().Has("small") {
+			_, _ = io.WriteString(w, "{}")
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+
+	serve := func(target, acceptEncoding string) *http.Response {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Result()
+	}
+
+	response := serve("/config", "gzip")
+	assert.Equal(t, "gzip", response.Header.Get("Content-Encoding"))
+	assert.Equal(t, []string{"X-Device-ID", "Accept-Encoding"}, response.Header.Values("Vary"))
+	reader, err := gzip.NewReader(response.Body)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data))
+
+	response = serve("/config", "br")
+	assert.Equal(t, "br", response.Header.Get("Content-Encoding"))
+	data, err = io.ReadAll(brotli.NewReader(response.Body))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data))
+
+	// Small responses and responses to clients without compression are sent as is
+	for _, response := range []*http.Response{serve("/config?small", "gzip"), serve("/config", "")} {
+		assert.Empty(t, response.Header.Get("Content-Encoding"))
+		assert.Contains(t, response.Header.Values("Vary"), "Accept-Encoding")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"sw-config-api/internal/api"
 	apperr "sw-config-api/internal/errors"
+	"sw-config-api/internal/requestid"
 
 	"github.com/ogen-go/ogen/ogenerrors"
 )
@@ -18,30 +19,34 @@ func CustomErrorHandler(ctx context.Context, w http.ResponseWriter, r *http.Requ
 	// Set content type
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 
+	// Error bodies carry the request ID, so that clients can report it
+	requestID := requestid.FromContext(ctx)
+	logger = logger.With("request_id", requestID)
+
 	// Handle different types of errors using errors.As for wrapped errors
 	var decodeParamsErr *ogenerrors.DecodeParamsError
 	if errors.As(err, &decodeParamsErr) {
 		// Handle parameter decoding errors
-		handleDecodeParamsError(logger, w, decodeParamsErr)
+		handleDecodeParamsError(logger, w, decodeParamsErr, requestID)
 		return
 	}
 
 	var decodeParamErr *ogenerrors.DecodeParamError
 	if errors.As(err, &decodeParamErr) {
 		// Handle single parameter decoding errors
-		handleDecodeParamError(logger, w, decodeParamErr)
+		handleDecodeParamError(logger, w, decodeParamErr, requestID)
 		return
 	}
 
 	// Handle other errors
-	handleGenericError(logger, w, err)
+	handleGenericError(logger, w, err, requestID)
 }
 
-func handleDecodeParamsError(logger *slog.Logger, w http.ResponseWriter, err *ogenerrors.DecodeParamsError) {
+func handleDecodeParamsError(logger *slog.Logger, w http.ResponseWriter, err *ogenerrors.DecodeParamsError, requestID string) {
 	// Extract the underlying parameter error
 	var decodeErr *ogenerrors.DecodeParamError
 	if errors.As(err.Err, &decodeErr) {
-		handleDecodeParamError(logger, w, decodeErr)
+		handleDecodeParamError(logger, w, decodeErr, requestID)
 		return
 	}
 
@@ -56,8 +61,9 @@ func handleDecodeParamsError(logger *slog.Logger, w http.ResponseWriter, err *og
 	// Use generated error types
 	errorResponse := &api.ConfigGetBadRequest{
 		Error: api.NewOptConfigGetBadRequestError(api.ConfigGetBadRequestError{
-			Code:    api.NewOptInt(400),
-			Message: api.NewOptString("Invalid request parameters"),
+			Code:      api.NewOptInt(400),
+			Message:   api.NewOptString("Invalid request parameters"),
+			RequestID: api.NewOptString(requestID),
 		}),
 	}
 
@@ -67,7 +73,7 @@ func handleDecodeParamsError(logger *slog.Logger, w http.ResponseWriter, err *og
 	}
 }
 
-func handleDecodeParamError(logger *slog.Logger, w http.ResponseWriter, err *ogenerrors.DecodeParamError) {
+func handleDecodeParamError(logger *slog.Logger, w http.ResponseWriter, err *ogenerrors.DecodeParamError, requestID string) {
 	w.WriteHeader(http.StatusBadRequest)
 
 	// Create user-friendly error message
@@ -82,8 +88,9 @@ func handleDecodeParamError(logger *slog.Logger, w http.ResponseWriter, err *oge
 	// Use generated error types
 	errorResponse := &api.ConfigGetBadRequest{
 		Error: api.NewOptConfigGetBadRequestError(api.ConfigGetBadRequestError{
-			Code:    api.NewOptInt(400),
-			Message: api.NewOptString(message),
+			Code:      api.NewOptInt(400),
+			Message:   api.NewOptString(message),
+			RequestID: api.NewOptString(requestID),
 		}),
 	}
 
@@ -93,7 +100,7 @@ func handleDecodeParamError(logger *slog.Logger, w http.ResponseWriter, err *oge
 	}
 }
 
-func handleGenericError(logger *slog.Logger, w http.ResponseWriter, err error) {
+func handleGenericError(logger *slog.Logger, w http.ResponseWriter, err error, requestID string) {
 	// Check if it's a not found error
 	if apperr.IsNotFoundError(err) {
 		w.WriteHeader(http.StatusNotFound)
@@ -101,8 +108,9 @@ func handleGenericError(logger *slog.Logger, w http.ResponseWriter, err error) {
 		// Use generated error types
 		errorResponse := &api.ConfigGetNotFound{
 			Error: api.NewOptConfigGetNotFoundError(api.ConfigGetNotFoundError{
-				Code:    api.NewOptInt(404),
-				Message: api.NewOptString("Configuration not found"),
+				Code:      api.NewOptInt(404),
+				Message:   api.NewOptString("Configuration not found"),
+				RequestID: api.NewOptString(requestID),
 			}),
 		}
 
@@ -119,8 +127,9 @@ func handleGenericError(logger *slog.Logger, w http.ResponseWriter, err error) {
 	// For 500 errors, we still need to use a generic response since there's no generated type
 	response := map[string]interface{}{
 		"error": map[string]interface{}{
-			"code":    500,
-			"message": "Internal server error",
+			"code":       500,
+			"message":    "Internal server error",
+			"request_id": requestID,
 		},
 	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"time"
+
+	apperr "sw-config-api/internal/errors"
+	"sw-config-api/internal/logging"
+	"sw-config-api/internal/requestid"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCTracePropagation is TracePropagation for gRPC, the trace context is read from metadata
+func GRPCTracePropagation(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	return handler(ctx, req)
+}
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GRPCMetrics is MetricsMiddleware for gRPC, recording request duration per method and status code
+func GRPCMetrics(meter metric.Meter) (grpc.UnaryServerInterceptor, error) {
+	duration, err := meter.Float64Histogram(
+		"rpc.server.duration",
+		metric.WithDescription("Duration of gRPC requests by method and status code"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		response, err := handler(ctx, req)
+
+		duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+			attribute.String("method", info.FullMethod),
+			attribute.String("code", status.Code(err).String()),
+		))
+		return response, err
+	}, nil
+}
+
+// GRPCErrors is CustomErrorHandler for gRPC. Errors are mapped to statuses with the codes
+// of the REST API: missing configurations to NOT_FOUND, unexpected errors to INTERNAL without
+// their details. Every status carries the request ID in a google.rpc.RequestInfo detail.
+func GRPCErrors(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	response, err := handler(ctx, req)
+	if err == nil {
+		return response, nil
+	}
+	return nil, grpcStatus(ctx, err)
+}
+
+// grpcStatus maps the error to the status returned to the caller
+func grpcStatus(ctx context.Context, err error) error {
+	st, ok := status.FromError(err)
+	switch {
+	case ok:
+	case apperr.IsNotFoundError(err):
+		st = status.New(codes.NotFound, "Configuration not found")
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		st = status.FromContextError(err)
+	default:
+		st = status.New(codes.Internal, "Internal server error")
+	}
+
+	// Error statuses carry the request ID, so that callers can report it
+	if detailed, detailsErr := st.WithDetails(&errdetails.RequestInfo{RequestId: requestid.FromContext(ctx)}); detailsErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// GRPCRecovery turns panics of handlers into INTERNAL statuses. Unlike net/http, gRPC does not
+// recover panics, and one would stop the process with the REST API. It must run outside
+// GRPCErrors and GRPCLogging, so that panics in them are recovered too.
+func GRPCRecovery(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response any, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger.Error("request panicked",
+					"request_id", requestid.FromContext(ctx),
+					"method", info.FullMethod,
+					"panic", fmt.Sprint(recovered),
+					"stack", string(debug.Stack()),
+				)
+				response, err = nil, grpcStatus(ctx, fmt.Errorf("panic: %v", recovered))
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// GRPCLogging is LoggingMiddleware for gRPC. Start and completion are logged for samplePercent
+// of requests, failed requests are always logged. It must run inside GRPCErrors, so that
+// the original error is logged rather than the status returned to the caller.
+func GRPCLogging(logger *slog.Logger, samplePercent int) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		requestLogger := logger.With(
+			"request_id", requestid.FromContext(ctx),
+			"method", info.FullMethod,
+		)
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if userAgent := md.Get("user-agent"); len(userAgent) > 0 {
+				requestLogger = requestLogger.With("user_agent", userAgent[0])
+			}
+		}
+		ctx = logging.NewContext(ctx, requestLogger)
+
+		sampled := samplePercent >= 100 || rand.IntN(100) < samplePercent
+		if sampled {
+			requestLogger.Info("request started")
+		}
+
+		response, err := handler(ctx, req)
+
+		duration := time.Since(start)
+		switch code := status.Code(err); {
+		case err == nil:
+			if sampled {
+				requestLogger.Info("request completed", "duration_ms", duration.Milliseconds())
+			}
+		case code == codes.InvalidArgument, code == codes.NotFound:
+			// Invalid requests and missing configurations are expected, as 400 and 404 of the REST API
+			requestLogger.Warn("request rejected",
+				"duration_ms", duration.Milliseconds(),
+				"code", code.String(),
+				"error", err.Error(),
+			)
+		default:
+			requestLogger.Error("request failed",
+				"duration_ms", duration.Milliseconds(),
+				"error", err.Error(),
+			)
+		}
+
+		return response, err
+	}
+}
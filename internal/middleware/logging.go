@@ -1,36 +1,32 @@
 package middleware
 
 import (
-	"context"
 	"log/slog"
-	"strings"
+	"math/rand/v2"
 	"time"
 
 	"sw-config-api/internal/api"
 	apperr "sw-config-api/internal/errors"
+	"sw-config-api/internal/logging"
+	"sw-config-api/internal/requestid"
 
 	"github.com/ogen-go/ogen/middleware"
-	"github.com/rs/xid"
 )
 
-// contextKey is a custom type for context keys to avoid collisions
-type contextKey string
-
-const (
-	requestIDKey contextKey = "request_id"
-)
-
-// LoggingMiddleware creates middleware for logging HTTP requests
-func LoggingMiddleware(logger *slog.Logger) api.Middleware {
+// LoggingMiddleware creates middleware for logging HTTP requests.
+// Start, parameters and completion are logged for samplePercent of requests,
+// failed requests are always logged. The request logger is put into the context,
+// so that logs of services and repositories carry the request ID.
+func LoggingMiddleware(logger *slog.Logger, samplePercent int) api.Middleware {
 	return func(req middleware.Request, next middleware.Next) (middleware.Response, error) {
 		start := time.Now()
 
-		// Generate unique request ID
-		requestID := generateRequestID()
-
-		// Add request ID to context
-		ctx := context.WithValue(req.Context, requestIDKey, requestID)
-		req.Context = ctx
+		// The request ID is assigned by requestid.Middleware before the request reaches ogen
+		requestID := requestid.FromContext(req.Context)
+		if requestID == "" {
+			requestID = requestid.New()
+			req.Context = requestid.NewContext(req.Context, requestID)
+		}
 
 		// Create logger with request context
 		requestLogger := logger.With(
@@ -43,12 +39,17 @@ func LoggingMiddleware(logger *slog.Logger) api.Middleware {
 			"operation", req.OperationName,
 			"operation_summary", req.OperationSummary,
 		)
+		req.Context = logging.NewContext(req.Context, requestLogger)
+
+		sampled := samplePercent >= 100 || rand.IntN(100) < samplePercent
 
 		// Log request start
-		requestLogger.Info("request started")
+		if sampled {
+			requestLogger.Info("request started")
+		}
 
 		// Log request parameters at DEBUG level
-		if len(req.Params) > 0 {
+		if sampled && len(req.Params) > 0 {
 			params := make(map[string]interface{})
 			for key, value := range req.Params {
 				params[key.Name] = value
@@ -76,7 +77,7 @@ func LoggingMiddleware(logger *slog.Logger) api.Middleware {
 					"error", err.Error(),
 				)
 			}
-		} else {
+		} else if sampled {
 			requestLogger.Info("request completed",
 				"duration_ms", duration.Milliseconds(),
 			)
@@ -85,17 +86,3 @@ func LoggingMiddleware(logger *slog.Logger) api.Middleware {
 		return response, err
 	}
 }
-
-// generateRequestID generates a unique ID for the request using strings.Builder
-func generateRequestID() string {
-	var builder strings.Builder
-
-	// Add timestamp prefix
-	builder.WriteString(time.Now().Format("20060102150405"))
-	builder.WriteString("-")
-
-	// Add unique identifier using xid
-	builder.WriteString(xid.New().String())
-
-	return builder.String()
-}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"sw-config-api/internal/api"
+	apperr "sw-config-api/internal/errors"
+
+	"github.com/ogen-go/ogen/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// MetricsMiddleware creates middleware recording request duration per operation and response code
+func MetricsMiddleware(meter metric.Meter) (api.Middleware, error) {
+	duration, err := meter.Float64Histogram(
+		"http.server.request.duration",
+		metric.WithDescription("Duration of API requests by operation and response code"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(req middleware.Request, next middleware.Next) (middleware.Response, error) {
+		start := time.Now()
+
+		response, err := next(req)
+
+		duration.Record(req.Context, time.Since(start).Seconds(), metric.WithAttributes(
+			attribute.String("operation", req.OperationName),
+			attribute.String("code", strconv.Itoa(responseCode(response, err))),
+		))
+		return response, err
+	}, nil
+}
+
+// responseCode returns the HTTP status code the response is written with
+func responseCode(response middleware.Response, err error) int {
+	if err != nil {
+		if apperr.IsNotFoundError(err) {
+			return http.StatusNotFound
+		}
+		return http.StatusInternalServerError
+	}
+
+	switch response.Type.(type) {
+	case *api.ConfigGetNotFound:
+		return http.StatusNotFound
+	case *api.ConfigGetBadRequest:
+		return http.StatusBadRequest
+	default:
+		return http.StatusOK
+	}
+}
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sw-config-api/internal/api"
+	"sw-config-api/internal/ratelimit"
+	"sw-config-api/internal/requestid"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Keys clients are rate limited by
+const (
+	RateLimitKeyIP       = "ip"
+	RateLimitKeyDevice   = "device"
+	RateLimitKeyPlatform = "platform"
+)
+
+// DeviceIDHeader identifies the device of a mobile client
+const DeviceIDHeader = "X-Device-ID"
+
+// maxDeviceIDLength limits device IDs used as rate limit keys
+const maxDeviceIDLength = 128
+
+// overloadRetryAfter is suggested to clients when load is shed
+const overloadRetryAfter = time.Second
+
+// KeyFunc returns the key a request is rate limited by
+type KeyFunc func(r *http.Request) string
+
+// RateLimitKey returns the key function for the kind of key. Requests without a device ID
+// are limited by IP. trustedProxies is the number of proxies in front of the service,
+// see ClientIP.
+func RateLimitKey(kind string, trustedProxies int) (KeyFunc, error) {
+	clientIP := func(r *http.Request) string {
+		return "ip:" + ClientIP(r, trustedProxies)
+	}
+
+	switch kind {
+	case RateLimitKeyIP:
+		return clientIP, nil
+	case RateLimitKeyDevice:
+		return func(r *http.Request) string {
+			deviceID := r.Header.Get(DeviceIDHeader)
+			if deviceID == "" {
+				deviceID = r.URL.Query().Get("deviceId")
+			}
+			if deviceID == "" || len(deviceID) > maxDeviceIDLength {
+				return clientIP(r)
+			}
+			return "device:" + deviceID
+		}, nil
+	case RateLimitKeyPlatform:
+		return func(r *http.Request) string {
+			return "platform:" + r.URL.Query().Get("platform")
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported rate limit key: %s", kind)
+	}
+}
+
+// ClientIP returns the IP of the client. Behind trustedProxies proxies the address is taken
+// from X-Forwarded-For. Every proxy appends the address it received the request from, and
+// earlier entries are sent by the client, so the address added by the outermost trusted proxy
+// is the trustedProxies-th from the right. 0 ignores the header.
+func ClientIP(r *http.Request, trustedProxies int) string {
+	if trustedProxies > 0 {
+		// Several headers are one list
+		var addresses []string
+		for _, header := range r.Header.Values("X-Forwarded-For") {
+			for _, address := range strings.Split(header, ",") {
+				addresses = append(addresses, strings.TrimSpace(address))
+			}
+		}
+		if len(addresses) > 0 {
+			// A shorter list was appended by the trusted proxies only
+			return addresses[max(len(addresses)-trustedProxies, 0)]
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rejections counts requests rejected before reaching the API
+type rejections struct {
+	counter metric.Int64Counter
+}
+
+func newRejections(meter metric.Meter) (*rejections, error) {
+	counter, err := meter.Int64Counter(
+		"http.server.rejected",
+		metric.WithDescription("Number of requests rejected by rate limiting or load shedding by reason"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rejections{counter: counter}, nil
+}
+
+func (m *rejections) add(r *http.Request, reason string) {
+	m.counter.Add(r.Context(), 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+// RateLimit rejects requests of clients exceeding the limit with 429 and Retry-After.
+// Requests are allowed when the limiter fails, rate limiting must not take the service down.
+func RateLimit(limiter ratelimit.Limiter, key KeyFunc, meter metric.Meter, logger *slog.Logger) (func(http.Handler) http.Handler, error) {
+	rejected, err := newRejections(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, retryAfter, err := limiter.Allow(r.Context(), key(r))
+			if err != nil {
+				logger.Error("rate limiter failed", "error", err.Error())
+				allowed = true
+			}
+			if !allowed {
+				rejected.add(r, "rate_limit")
+				writeRetryAfter(w, retryAfter)
+				writeError(w, http.StatusTooManyRequests, &api.ConfigGetTooManyRequests{
+					Error: api.NewOptConfigGetTooManyRequestsError(api.ConfigGetTooManyRequestsError{
+						Code:      api.NewOptInt(http.StatusTooManyRequests),
+						Message:   api.NewOptString("Too many requests"),
+						RequestID: api.NewOptString(requestid.FromContext(r.Context())),
+					}),
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// ConcurrencyLimit sheds requests with 503 while maxInFlight requests are being served,
+// so that a burst is rejected before it exhausts the database connection pool
+func ConcurrencyLimit(maxInFlight int, meter metric.Meter) (func(http.Handler) http.Handler, error) {
+	rejected, err := newRejections(meter)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make(chan struct{}, maxInFlight)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				next.ServeHTTP(w, r)
+			default:
+				rejected.add(r, "overload")
+				writeRetryAfter(w, overloadRetryAfter)
+				writeError(w, http.StatusServiceUnavailable, &api.ConfigGetServiceUnavailable{
+					Error: api.NewOptConfigGetServiceUnavailableError(api.ConfigGetServiceUnavailableError{
+						Code:      api.NewOptInt(http.StatusServiceUnavailable),
+						Message:   api.NewOptString("Service overloaded"),
+						RequestID: api.NewOptString(requestid.FromContext(r.Context())),
+					}),
+				})
+			}
+		})
+	}, nil
+}
+
+// writeRetryAfter sets Retry-After in whole seconds, rounded up
+func writeRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+}
+
+// writeError writes an error response of a generated type
+func writeError(w http.ResponseWriter, code int, response any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(response)
+}
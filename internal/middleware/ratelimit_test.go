@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"sw-config-api/internal/ratelimit"
+)
+
+func TestRateLimitKey(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/config?platform=android&appVersion=14.8.447", nil)
+	request.RemoteAddr = "10.0.0.1:51234"
+	request.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.2")
+
+	tests := []struct {
+		kind           string
+		trustedProxies int
+		deviceID       string
+		key            string
+	}{
+		{RateLimitKeyIP, 0, "", "ip:10.0.0.1"},
+		{RateLimitKeyIP, 1, "", "ip:10.0.0.2"},
+		{RateLimitKeyIP, 2, "", "ip:203.0.113.7"},
+		{RateLimitKeyDevice, 0, "device-1", "device:device-1"},
+		{RateLimitKeyDevice, 0, "", "ip:10.0.0.1"},
+		{RateLimitKeyPlatform, 0, "", "platform:android"},
+	}
+
+	for _, tt := range tests {
+		key, err := RateLimitKey(tt.kind, tt.trustedProxies)
+		require.NoError(t, err)
+
+		request.Header.Del(DeviceIDHeader)
+		if tt.deviceID != "" {
+			request.Header.Set(DeviceIDHeader, tt.deviceID)
+		}
+		assert.Equal(t, tt.key, key(request), tt.kind)
+	}
+
+	_, err := RateLimitKey("user", 0)
+	assert.Error(t, err)
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		forwarded      []string
+		trustedProxies int
+		ip             string
+	}{
+		{"addresses sent by the client are ignored", []string{"198.51.100.1, 203.0.113.7"}, 1, "203.0.113.7"},
+		{"address added by the outermost of two proxies", []string{"198.51.100.1, 203.0.113.7, 10.0.0.2"}, 2, "203.0.113.7"},
+		{"several headers form one list", []string{"198.51.100.1", "203.0.113.7"}, 1, "203.0.113.7"},
+		{"list shorter than the proxy chain", []string{"203.0.113.7"}, 2, "203.0.113.7"},
+		{"header is ignored without trusted proxies", []string{"203.0.113.7"}, 0, "10.0.0.1"},
+		{"remote address without the header", nil, 1, "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/config", nil)
+			request.RemoteAddr = "10.0.0.1:51234"
+			for _, forwarded := range tt.forwarded {
+				request.Header.Add("X-Forwarded-For", forwarded)
+			}
+			assert.Equal(t, tt.ip, ClientIP(request, tt.trustedProxies))
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	key, err := RateLimitKey(RateLimitKeyIP, 0)
+	require.NoError(t, err)
+
+	limit, err := RateLimit(
+		ratelimit.NewMemoryLimiter(ratelimit.Limit{Rate: 0.5, Burst: 1}, 10),
+		key,
+		noop.NewMeterProvider().Meter(""),
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	require.NoError(t, err)
+	handler := limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config", nil))
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusOK, serve().Code)
+
+	recorder := serve()
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error": {"code": 429, "message": "Too many requests", "request_id": ""}}`, recorder.Body.String())
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	limit, err := ConcurrencyLimit(1, noop.NewMeterProvider().Meter(""))
+	require.NoError(t, err)
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/config", nil))
+	}()
+
+	select {
+	case <-entered:
+	case <-time.After(time.Second):
+		t.Fatal("first request was not served")
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/config", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("Retry-After"))
+
+	close(release)
+	wg.Wait()
+}
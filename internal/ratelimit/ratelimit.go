@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"sw-config-api/internal/cache"
+)
+
+// Limiter decides whether a request of the client identified by key is allowed
+type Limiter interface {
+	// Allow takes a token of the key. When the request is rejected it returns
+	// how long the client should wait before retrying.
+	Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// Limit is a token bucket: Rate tokens per second are added up to Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// bucket is the token bucket of a single key
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// MemoryLimiter keeps token buckets of the instance in memory
+type MemoryLimiter struct {
+	limit   Limit
+	maxKeys int
+	now     func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	order   *list.List // front is the bucket of the most recent request
+}
+
+// NewMemoryLimiter creates a limiter tracking at most maxKeys clients.
+// When the limit is reached the bucket of the least recently seen client is dropped:
+// it has had the longest time to refill, and clients that keep being limited stay tracked.
+func NewMemoryLimiter(limit Limit, maxKeys int) *MemoryLimiter {
+	return &MemoryLimiter{
+		limit:   limit,
+		maxKeys: max(maxKeys, 1),
+		now:     time.Now,
+		buckets: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Allow takes a token from the bucket of the key
+func (l *MemoryLimiter) Allow(_ context.Context, key string) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var b *bucket
+	if element, ok := l.buckets[key]; ok {
+		l.order.MoveToFront(element)
+		b = element.Value.(*bucket)
+	} else {
+		if len(l.buckets) >= l.maxKeys {
+			l.evict()
+		}
+		b = &bucket{key: key, tokens: float64(l.limit.Burst), last: now}
+		l.buckets[key] = l.order.PushFront(b)
+	}
+
+	l.refill(b, now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+
+	wait := time.Duration(math.Ceil((1 - b.tokens) / l.limit.Rate * float64(time.Second)))
+	return false, wait, nil
+}
+
+// refill adds tokens accumulated since the last request
+func (l *MemoryLimiter) refill(b *bucket, now time.Time) {
+	b.tokens = math.Min(float64(l.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*l.limit.Rate)
+	b.last = now
+}
+
+// evict drops the bucket of the least recently seen client
+func (l *MemoryLimiter) evict() {
+	if element := l.order.Back(); element != nil {
+		l.order.Remove(element)
+		delete(l.buckets, element.Value.(*bucket).key)
+	}
+}
+
+// TokenStore keeps token buckets shared between instances
+type TokenStore interface {
+	TakeToken(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+// RedisLimiter shares token buckets between instances through Redis.
+// While Redis is unavailable requests are limited per instance by the fallback limiter.
+type RedisLimiter struct {
+	store    TokenStore
+	limit    Limit
+	fallback Limiter
+	logger   *slog.Logger
+}
+
+// NewRedisLimiter creates a limiter shared between instances
+func NewRedisLimiter(store TokenStore, limit Limit, fallback Limiter, logger *slog.Logger) *RedisLimiter {
+	return &RedisLimiter{
+		store:    store,
+		limit:    limit,
+		fallback: fallback,
+		logger:   logger,
+	}
+}
+
+// Allow takes a token from the shared bucket of the key
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	allowed, retryAfter, err := l.store.TakeToken(ctx, key, l.limit.Rate, l.limit.Burst)
+	if err != nil {
+		if !errors.Is(err, cache.ErrUnavailable) {
+			l.logger.Error("failed to take rate limit token", "error", err.Error())
+		}
+		return l.fallback.Allow(ctx, key)
+	}
+	return allowed, retryAfter, nil
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sw-config-api/internal/cache"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+
+	limiter := NewMemoryLimiter(Limit{Rate: 2, Burst: 3}, 10)
+	limiter.now = func() time.Time { return now }
+
+	// The burst is allowed at once
+	for range 3 {
+		allowed, _, err := limiter.Allow(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, retryAfter, err := limiter.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// Other clients have their own buckets
+	allowed, _, _ = limiter.Allow(ctx, "10.0.0.2")
+	assert.True(t, allowed)
+
+	// A token is added every 1/rate seconds
+	now = now.Add(500 * time.Millisecond)
+	allowed, _, _ = limiter.Allow(ctx, "10.0.0.1")
+	assert.True(t, allowed)
+	allowed, _, _ = limiter.Allow(ctx, "10.0.0.1")
+	assert.False(t, allowed)
+}
+
+func TestMemoryLimiter_EvictsLeastRecentlySeen(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+
+	limiter := NewMemoryLimiter(Limit{Rate: 1, Burst: 1}, 2)
+	limiter.now = func() time.Time { return now }
+
+	_, _, _ = limiter.Allow(ctx, "a")
+	now = now.Add(time.Second)
+	_, _, _ = limiter.Allow(ctx, "b")
+
+	// "a" is the least recently seen client and is dropped for "c"
+	_, _, _ = limiter.Allow(ctx, "c")
+	assert.Len(t, limiter.buckets, 2)
+	assert.NotContains(t, limiter.buckets, "a")
+
+	// "b" is still limited
+	allowed, _, _ := limiter.Allow(ctx, "b")
+	assert.False(t, allowed)
+}
+
+func TestMemoryLimiter_KeepsLimitedClientsWhenFull(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+
+	limiter := NewMemoryLimiter(Limit{Rate: 1, Burst: 1}, 100)
+	limiter.now = func() time.Time { return now }
+
+	allowed, _, _ := limiter.Allow(ctx, "limited")
+	assert.True(t, allowed)
+
+	// Every request of a client changing its key creates a bucket, the limited client keeps retrying
+	for i := range 1000 {
+		_, _, _ = limiter.Allow(ctx, fmt.Sprintf("spoofed-%d", i))
+		if i%50 == 0 {
+			allowed, _, _ = limiter.Allow(ctx, "limited")
+			assert.False(t, allowed)
+		}
+	}
+
+	assert.Len(t, limiter.buckets, 100)
+	assert.Equal(t, 100, limiter.order.Len())
+	allowed, _, _ = limiter.Allow(ctx, "limited")
+	assert.False(t, allowed)
+}
+
+// stubStore is a TokenStore returning a fixed error
+type stubStore struct{ err error }
+
+func (s stubStore) TakeToken(context.Context, string, float64, int) (bool, time.Duration, error) {
+	return false, 0, s.err
+}
+
+func TestRedisLimiter_FallsBackWhenUnavailable(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, err := range []error{cache.ErrUnavailable, errors.New("connection reset")} {
+		fallback := NewMemoryLimiter(Limit{Rate: 1, Burst: 1}, 10)
+		limiter := NewRedisLimiter(stubStore{err: err}, Limit{Rate: 1, Burst: 1}, fallback, logger)
+
+		allowed, _, err := limiter.Allow(context.Background(), "10.0.0.1")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+
+		allowed, _, _ = limiter.Allow(context.Background(), "10.0.0.1")
+		assert.False(t, allowed, "the fallback limits the instance")
+	}
+}
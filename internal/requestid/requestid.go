@@ -0,0 +1,95 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Header carries the request ID between the client, gateways and the service
+const Header = "X-Request-ID"
+
+// MaxLength limits IDs accepted from callers, so that they cannot bloat logs
+const MaxLength = 128
+
+// contextKey is a custom type for context keys to avoid collisions
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx or an empty string
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New generates a unique ID prefixed with the current time
+func New() string {
+	var builder strings.Builder
+
+	// Add timestamp prefix
+	builder.WriteString(time.Now().Format("20060102150405"))
+	builder.WriteString("-")
+
+	// Add unique identifier using xid
+	builder.WriteString(xid.New().String())
+
+	return builder.String()
+}
+
+// Valid reports whether an ID received from a caller can be used as is.
+// Only letters, digits and -_.: are allowed, so IDs are safe to log and echo in headers.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Middleware puts the request ID into the request context and the response header.
+// A valid ID sent by the caller is kept, otherwise a new one is generated.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !Valid(id) {
+			id = New()
+		}
+
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// UnaryServerInterceptor is Middleware for gRPC. The ID is read from the x-request-id
+// metadata and returned in the x-request-id response header.
+func UnaryServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(Header); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if !Valid(id) {
+		id = New()
+	}
+
+	// The header is sent with the response or the error status
+	_ = grpc.SetHeader(ctx, metadata.Pairs(Header, id))
+	return handler(NewContext(ctx, id), req)
+}
@@ -0,0 +1,85 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{"20250101120000-d0fk2s8n3c5g00b1m2p0", true},
+		{"3f0c6c1e-8b5a-4d3c-9a57-0f4f1f2b6e11", true},
+		{"gw:eu-1.trace_42", true},
+		{"", false},
+		{"id with spaces", false},
+		{"id\nforged log line", false},
+		{"<script>", false},
+		{strings.Repeat("a", MaxLength), true},
+		{strings.Repeat("a", MaxLength+1), false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, Valid(tt.id), tt.id)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var seen string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FromContext(r.Context())
+	}))
+
+	serve := func(id string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/config", nil)
+		if id != "" {
+			request.Header.Set(Header, id)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// An ID of the caller is kept
+	recorder := serve("gateway-42")
+	assert.Equal(t, "gateway-42", seen)
+	assert.Equal(t, "gateway-42", recorder.Header().Get(Header))
+
+	// Missing and invalid IDs are replaced
+	for _, id := range []string{"", "bad id"} {
+		recorder = serve(id)
+		assert.True(t, Valid(seen))
+		assert.NotEqual(t, id, seen)
+		assert.Equal(t, seen, recorder.Header().Get(Header))
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := func(id string) string {
+		ctx := context.Background()
+		if id != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(Header, id))
+		}
+		response, _ := UnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			return FromContext(ctx), nil
+		})
+		return response.(string)
+	}
+
+	// An ID of the caller is kept, missing and invalid IDs are replaced
+	assert.Equal(t, "gateway-42", intercept("gateway-42"))
+	for _, id := range []string{"", "bad id"} {
+		seen := intercept(id)
+		assert.True(t, Valid(seen))
+		assert.NotEqual(t, id, seen)
+	}
+}
@@ -3,75 +3,384 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
 	"sw-config-api/internal/cache"
+	"sw-config-api/internal/logging"
+
+	"github.com/Masterminds/semver"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 )
 
-// CachedConfigService wraps ConfigService with caching
+// CachedConfigService wraps ConfigService with caching.
+// Concurrent cache misses for the same key are coalesced into a single ConfigService call.
+//
+// The cache has two stages. A resolution maps the platform, MAJOR.MINOR of the app version
+// and explicitly requested versions to the selected resources, and the assembled configuration
+// is cached by those resources. Patch releases of the app share one configuration entry,
+// and configurations of one assets version can be dropped without touching the others.
+//
+// A cached configuration is fresh for ttl. After that it is served as stale for staleTTL
+// while it is refreshed in the background, so clients keep getting the last known
+// configuration when the storage is unavailable.
 type CachedConfigService struct {
 	configService *ConfigService
 	cache         cache.Interface
 	ttl           time.Duration
+	staleTTL      time.Duration
 	logger        *slog.Logger
+	metrics       *cacheMetrics
+	tracer        trace.Tracer
+	group         singleflight.Group
+
+	// Optional lock shared between instances so that only one of them recomputes a key
+	locker   cache.Locker
+	lockTTL  time.Duration
+	lockWait time.Duration
+}
+
+// cacheEntry is a cached value with the time it stays fresh until.
+// Entries are stored in cache for ttl + staleTTL.
+type cacheEntry[T any] struct {
+	Value      *T        `json:"value"`
+	FreshUntil time.Time `json:"fresh_until"`
 }
 
 // NewCachedConfigService creates a new cached config service
 func NewCachedConfigService(configService *ConfigService, cache cache.Interface, ttl time.Duration, logger *slog.Logger) *CachedConfigService {
+	metrics, _ := newCacheMetrics(noopMeter) // Instruments of the no-op meter never fail
+
 	return &CachedConfigService{
 		configService: configService,
 		cache:         cache,
 		ttl:           ttl,
 		logger:        logger,
+		metrics:       metrics,
+		tracer:        tracer,
 	}
 }
 
+// EnableMetrics reports cache lookups and failed cache operations to the meter
+func (s *CachedConfigService) EnableMetrics(meter metric.Meter) error {
+	metrics, err := newCacheMetrics(meter)
+	if err != nil {
+		return err
+	}
+	s.metrics = metrics
+	return nil
+}
+
+// EnableTracing creates spans of cache operations with the provider instead of the global one
+func (s *CachedConfigService) EnableTracing(provider trace.TracerProvider) {
+	s.tracer = provider.Tracer(tracerName)
+}
+
+// EnableLocking makes instances take a lock before recomputing a missing key.
+// Instances that fail to take the lock wait up to lockWait for the value to appear in cache
+// and compute it themselves if it does not.
+func (s *CachedConfigService) EnableLocking(locker cache.Locker, lockTTL, lockWait time.Duration) {
+	s.locker = locker
+	s.lockTTL = lockTTL
+	s.lockWait = lockWait
+}
+
+// EnableServeStale keeps configurations in cache for staleTTL after they expire.
+// Expired configurations are served with the Stale flag and refreshed in the background.
+func (s *CachedConfigService) EnableServeStale(staleTTL time.Duration) {
+	s.staleTTL = staleTTL
+}
+
+// lockPollInterval is how often an instance waiting for another one checks the cache
+const lockPollInterval = 25 * time.Millisecond
+
 // GetConfiguration retrieves configuration with caching
-func (s *CachedConfigService) GetConfiguration(ctx context.Context, params ClientParams) (*Configuration, error) {
-	// Generate cache key based on parameters
-	cacheKey := s.generateCacheKey(params)
+func (s *CachedConfigService) GetConfiguration(ctx context.Context, params ClientParams) (_ *Configuration, err error) {
+	ctx, span := s.tracer.Start(ctx, "CachedConfigService.GetConfiguration")
+	defer func() { endSpan(span, err) }()
+
+	// Requests resolving to the same resources share the resolution key
+	resolutionKey := resolutionCacheKey(params)
 
 	// Try to get from cache first
-	if cached, exists := s.cache.Get(cacheKey); exists {
-		var config Configuration
-		if err := json.Unmarshal(cached, &config); err == nil {
-			return &config, nil
+	if config, fresh, ok := s.getCached(ctx, resolutionKey); ok {
+		if fresh {
+			s.metrics.result(ctx, cacheResultHit)
+			span.SetAttributes(attribute.String("cache.result", cacheResultHit))
+			return config, nil
 		}
+
+		// Serve the stale configuration and refresh it in the background
+		s.metrics.result(ctx, cacheResultStale)
+		span.SetAttributes(attribute.String("cache.result", cacheResultStale))
+		s.refreshInBackground(ctx, resolutionKey, params)
+		config.Stale = true
+		return config, nil
+	}
+
+	s.metrics.result(ctx, cacheResultMiss)
+	span.SetAttributes(attribute.String("cache.result", cacheResultMiss))
+
+	// Coalesce concurrent misses. The shared call must not depend on the first caller's
+	// cancellation, every caller still stops waiting when its own context is done.
+	result := s.group.DoChan(resolutionKey, func() (any, error) {
+		return s.load(context.WithoutCancel(ctx), resolutionKey, params)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*Configuration), nil
+	}
+}
+
+// refreshInBackground reloads a stale configuration without blocking the request.
+// On storage errors the stale configuration stays in cache until it expires,
+// resolutions of configurations that no longer exist are removed.
+func (s *CachedConfigService) refreshInBackground(ctx context.Context, resolutionKey string, params ClientParams) {
+	// The result channel is buffered, so nobody has to read it
+	s.group.DoChan(resolutionKey, func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+		config, err := s.load(ctx, resolutionKey, params)
+		if err != nil {
+			if IsNotFoundError(err) {
+				if err := s.cache.Delete(ctx, resolutionKey); err != nil {
+					s.metrics.error(ctx, cacheOperationDelete)
+					logging.FromContext(ctx, s.logger).Error("failed to delete stale configuration",
+						"error", err.Error(),
+						"cache_key", resolutionKey,
+					)
+				}
+			} else {
+				s.metrics.error(ctx, cacheOperationRefresh)
+				logging.FromContext(ctx, s.logger).Error("failed to refresh configuration, serving stale",
+					"error", err.Error(),
+					"cache_key", resolutionKey,
+				)
+			}
+		}
+		return config, err
+	})
+}
+
+// getCached returns the configuration cached for the resolution key.
+// The configuration is fresh only if both the resolution and the configuration are fresh.
+func (s *CachedConfigService) getCached(ctx context.Context, resolutionKey string) (config *Configuration, fresh bool, ok bool) {
+	resolution, ok := getEntry[Resolution](ctx, s, resolutionKey)
+	if !ok {
+		return nil, false, false
+	}
+
+	entry, ok := getEntry[Configuration](ctx, s, configCacheKey(resolution.Value))
+	if !ok || !entry.Value.matches(resolution.Value) {
+		return nil, false, false
+	}
+	return entry.Value, resolution.isFresh() && entry.isFresh(), true
+}
+
+// getEntry returns a value stored in the cache of the service
+func getEntry[T any](ctx context.Context, s *CachedConfigService, key string) (*cacheEntry[T], bool) {
+	ctx, span := s.tracer.Start(ctx, "cache.get", trace.WithAttributes(attribute.String("cache.key", key)))
+	defer span.End()
+
+	cached, exists := s.cache.Get(ctx, key)
+	span.SetAttributes(attribute.Bool("cache.found", exists))
+	if !exists {
+		return nil, false
+	}
+
+	var entry cacheEntry[T]
+	if err := json.Unmarshal(cached, &entry); err != nil || entry.Value == nil {
 		// If unmarshal fails, continue to get fresh data
+		span.AddEvent("invalid cache entry")
+		return nil, false
 	}
+	return &entry, true
+}
 
-	// If not in cache, get from underlying service
-	config, err := s.configService.GetConfiguration(ctx, params)
-	if err != nil {
-		return nil, err
+// isFresh reports whether the value has not reached its soft expiry
+func (e *cacheEntry[T]) isFresh() bool {
+	return time.Now().Before(e.FreshUntil)
+}
+
+// matches reports whether the configuration was built for the resolution.
+// A resource hash may change without a new version, such configurations are rebuilt.
+func (c *Configuration) matches(resolution *Resolution) bool {
+	return c.Version.Required == resolution.PlatformVersion.RequiredVersion &&
+		c.Version.Store == resolution.PlatformVersion.StoreVersion &&
+		c.Assets.Version == resolution.Asset.Version &&
+		c.Assets.Hash == resolution.Asset.Hash &&
+		c.Definitions.Version == resolution.Definition.Version &&
+		c.Definitions.Hash == resolution.Definition.Hash
+}
+
+// load gets configuration from the underlying service and caches it
+func (s *CachedConfigService) load(ctx context.Context, resolutionKey string, params ClientParams) (*Configuration, error) {
+	// A previous coalesced call may have filled the cache already
+	if config, fresh, ok := s.getCached(ctx, resolutionKey); ok && fresh {
+		return config, nil
 	}
 
-	// Cache the result
-	if data, err := json.Marshal(config); err == nil {
-		if err := s.cache.Set(cacheKey, data, s.ttl); err != nil {
-			// Log cache error but don't fail the request
-			s.logger.Error("failed to cache configuration",
+	if s.locker != nil {
+		unlock, acquired, err := s.locker.TryLock(ctx, lockKeyPrefix+resolutionKey, s.lockTTL)
+		switch {
+		case errors.Is(err, cache.ErrUnavailable):
+			// Redis is down, compute the value without the lock
+		case err != nil:
+			// Lock is an optimization, compute the value without it
+			s.metrics.error(ctx, cacheOperationLock)
+			logging.FromContext(ctx, s.logger).Error("failed to acquire cache lock",
 				"error", err.Error(),
-				"cache_key", cacheKey,
+				"cache_key", resolutionKey,
 			)
+		case acquired:
+			defer unlock()
+		default:
+			if config, ok := s.waitForCache(ctx, resolutionKey); ok {
+				return config, nil
+			}
 		}
 	}
 
+	// If not in cache, resolve resources with the underlying service
+	resolution, err := s.configService.Resolve(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	s.setEntry(ctx, resolutionKey, &cacheEntry[Resolution]{Value: resolution, FreshUntil: time.Now().Add(s.ttl)})
+
+	// Another app version may have cached the configuration of these resources already
+	configKey := configCacheKey(resolution)
+	if entry, ok := getEntry[Configuration](ctx, s, configKey); ok && entry.isFresh() && entry.Value.matches(resolution) {
+		return entry.Value, nil
+	}
+
+	config, err := s.configService.Build(ctx, resolution)
+	if err != nil {
+		return nil, err
+	}
+	s.setEntry(ctx, configKey, &cacheEntry[Configuration]{Value: config, FreshUntil: time.Now().Add(s.ttl)})
+
 	return config, nil
 }
 
-// generateCacheKey creates a unique cache key based on request parameters
-// Format: config:{platform}:{appVersion}:{assetsVersion}:{definitionsVersion}
-func (s *CachedConfigService) generateCacheKey(params ClientParams) string {
+// setEntry stores the cache entry for ttl + staleTTL
+func (s *CachedConfigService) setEntry(ctx context.Context, key string, entry any) {
+	ctx, span := s.tracer.Start(ctx, "cache.set", trace.WithAttributes(attribute.String("cache.key", key)))
+	defer span.End()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+
+	err = s.cache.Set(ctx, key, data, s.ttl+s.staleTTL)
+	if err != nil {
+		span.RecordError(err)
+	}
+	if err != nil && !errors.Is(err, cache.ErrUnavailable) {
+		// Log cache error but don't fail the request
+		s.metrics.error(ctx, cacheOperationSet)
+		logging.FromContext(ctx, s.logger).Error("failed to cache configuration",
+			"error", err.Error(),
+			"cache_key", key,
+		)
+	}
+}
+
+// waitForCache waits for another instance holding the lock to cache a fresh configuration
+func (s *CachedConfigService) waitForCache(ctx context.Context, resolutionKey string) (*Configuration, bool) {
+	timer := time.NewTimer(s.lockWait)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-timer.C:
+			return nil, false
+		case <-ticker.C:
+			if config, fresh, ok := s.getCached(ctx, resolutionKey); ok && fresh {
+				return config, true
+			}
+		}
+	}
+}
+
+// Invalidate removes cached resolutions and configurations of the platforms,
+// empty list removes all of them
+func (s *CachedConfigService) Invalidate(ctx context.Context, platforms []string) error {
+	if len(platforms) == 0 {
+		return errors.Join(
+			s.cache.DeletePrefix(ctx, resolutionKeyPrefix),
+			s.cache.DeletePrefix(ctx, configKeyPrefix),
+		)
+	}
+
+	for _, platform := range platforms {
+		err := errors.Join(
+			s.cache.DeletePrefix(ctx, resolutionKeyPrefix+platform+":"),
+			s.cache.DeletePrefix(ctx, configKeyPrefix+platform+":"),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvalidateAssets removes resolutions of the platform and configurations built with the
+// assets versions. Configurations with other assets versions stay cached, so adding a new
+// assets version only makes clients resolve their resources again.
+func (s *CachedConfigService) InvalidateAssets(ctx context.Context, platform string, versions []string) error {
+	if err := s.cache.DeletePrefix(ctx, resolutionKeyPrefix+platform+":"); err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		if err := s.cache.DeletePrefix(ctx, configKeyPrefix+platform+":"+version+":"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cache key prefixes
+const (
+	resolutionKeyPrefix = "resolve:" // resolved resources by request parameters
+	configKeyPrefix     = "config:"  // configurations by resolved resources
+	lockKeyPrefix       = "lock:"    // locks taken while recomputing a configuration
+)
+
+// resolutionCacheKey creates a cache key of the resources selected for request parameters.
+// Resources are compatible by MAJOR.MINOR of the app version, so the patch is not part of the key.
+// Format: resolve:{platform}:{major}.{minor}:{assetsVersion}:{definitionsVersion}
+func resolutionCacheKey(params ClientParams) string {
 	var builder strings.Builder
 
 	// Build key with required parameters
-	builder.WriteString("config:")
+	builder.WriteString(resolutionKeyPrefix)
 	builder.WriteString(params.Platform)
 	builder.WriteString(":")
-	builder.WriteString(params.AppVersion)
+	if version, err := semver.NewVersion(params.AppVersion); err == nil {
+		builder.WriteString(fmt.Sprintf("%d.%d", version.Major(), version.Minor()))
+	} else {
+		builder.WriteString(params.AppVersion)
+	}
 
 	// Add optional assets version
 	builder.WriteString(":")
@@ -87,3 +396,10 @@ func (s *CachedConfigService) generateCacheKey(params ClientParams) string {
 
 	return builder.String()
 }
+
+// configCacheKey creates a cache key of the configuration assembled for resolved resources.
+// Assets version goes first so that configurations of one assets version share a prefix.
+// Format: config:{platform}:{assetsVersion}:{definitionsVersion}
+func configCacheKey(resolution *Resolution) string {
+	return configKeyPrefix + resolution.Platform + ":" + resolution.Asset.Version + ":" + resolution.Definition.Version
+}
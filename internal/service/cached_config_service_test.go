@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"sw-config-api/internal/cache"
+	"sw-config-api/internal/storage"
+)
+
+// cachedServiceMocks holds repository mocks behind a cached config service
+type cachedServiceMocks struct {
+	assets           *MockResourceRepo
+	definitions      *MockResourceRepo
+	assetURLs        *MockURLRepo
+	definitionURLs   *MockURLRepo
+	platformVersions *MockPlatformVersionRepository
+	entryPoints      *MockEntryPointRepository
+}
+
+func newCachedServiceMocks() *cachedServiceMocks {
+	return &cachedServiceMocks{
+		assets:           &MockResourceRepo{},
+		definitions:      &MockResourceRepo{},
+		assetURLs:        &MockURLRepo{},
+		definitionURLs:   &MockURLRepo{},
+		platformVersions: &MockPlatformVersionRepository{},
+		entryPoints:      &MockEntryPointRepository{},
+	}
+}
+
+func (m *cachedServiceMocks) configService() *ConfigService {
+	return NewConfigService(m.assets, m.definitions, m.assetURLs, m.definitionURLs, m.platformVersions, m.entryPoints)
+}
+
+// expectSuccess mocks a successful configuration resolution, the platform version lookup takes delay
+func (m *cachedServiceMocks) expectSuccess(delay time.Duration) {
+	m.platformVersions.On("GetPlatformVersion", mock.Anything, "android").
+		After(delay).
+		Return(&storage.PlatformVersion{RequiredVersion: "14.0.0", StoreVersion: "14.8.447"}, nil)
+	m.expectResources()
+}
+
+// expectResources mocks the lookups running concurrently with the platform version lookup
+func (m *cachedServiceMocks) expectResources() {
+	m.assets.On("GetCompatibleResource", mock.Anything, "android", "14.8.447").
+		Return(&storage.Resource{Version: "14.8.447", Hash: "abc123"}, nil)
+	m.definitions.On("GetCompatibleResource", mock.Anything, "android", "14.8.447").
+		Return(&storage.Resource{Version: "14.8.98", Hash: "def456"}, nil)
+	m.assetURLs.On("ListURLs", mock.Anything).Return([]string{"a.cdn.application.com"}, nil)
+	m.definitionURLs.On("ListURLs", mock.Anything).Return([]string{"d.cdn.application.com"}, nil)
+	m.entryPoints.On("Get", mock.Anything).Return(map[string]string{
+		"backend_entry_point": "api.application.com/jsonrpc/v2",
+	}, nil)
+}
+
+// cacheConfiguration stores the configuration for params as another instance would.
+// It is safe to call from other goroutines.
+func cacheConfiguration(t *testing.T, c cache.Interface, params ClientParams, config *Configuration, freshUntil time.Time) {
+	t.Helper()
+
+	resolution := &Resolution{
+		Platform:        params.Platform,
+		PlatformVersion: storage.PlatformVersion{RequiredVersion: config.Version.Required, StoreVersion: config.Version.Store},
+		Asset:           storage.Resource{Version: config.Assets.Version, Hash: config.Assets.Hash},
+		Definition:      storage.Resource{Version: config.Definitions.Version, Hash: config.Definitions.Hash},
+	}
+
+	data, err := json.Marshal(&cacheEntry[Resolution]{Value: resolution, FreshUntil: freshUntil})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Set(context.Background(), resolutionCacheKey(params), data, time.Hour))
+
+	data, err = json.Marshal(&cacheEntry[Configuration]{Value: config, FreshUntil: freshUntil})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Set(context.Background(), configCacheKey(resolution), data, time.Hour))
+}
+
+// stubLocker is a cache.Locker that never grants the lock
+type stubLocker struct{}
+
+func (stubLocker) TryLock(context.Context, string, time.Duration) (func(), bool, error) {
+	return nil, false, nil
+}
+
+func TestCachedConfigService_CoalescesConcurrentMisses(t *testing.T) {
+	mocks := newCachedServiceMocks()
+	mocks.expectSuccess(50 * time.Millisecond)
+
+	service := NewCachedConfigService(
+		mocks.configService(),
+		cache.NewMemoryCache(10),
+		time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	params := ClientParams{Platform: "android", AppVersion: "14.8.447"}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			config, err := service.GetConfiguration(context.Background(), params)
+			assert.NoError(t, err)
+			if assert.NotNil(t, config) {
+				assert.Equal(t, "14.8.447", config.Assets.Version)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mocks.platformVersions.AssertNumberOfCalls(t, "GetPlatformVersion", 1)
+}
+
+func TestCachedConfigService_WaitsForLockHolder(t *testing.T) {
+	mocks := newCachedServiceMocks()
+	memoryCache := cache.NewMemoryCache(10)
+
+	service := NewCachedConfigService(
+		mocks.configService(),
+		memoryCache,
+		time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	service.EnableLocking(stubLocker{}, time.Second, time.Second)
+
+	params := ClientParams{Platform: "android", AppVersion: "14.8.447"}
+
+	// Another instance holding the lock caches the configuration a bit later
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(50 * time.Millisecond)
+		cacheConfiguration(t, memoryCache, params, &Configuration{Assets: Resource{Version: "14.8.447"}}, time.Now().Add(time.Minute))
+	}()
+	defer func() { <-done }()
+
+	config, err := service.GetConfiguration(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, "14.8.447", config.Assets.Version)
+
+	// The value is taken from cache without querying repositories
+	mocks.platformVersions.AssertNotCalled(t, "GetPlatformVersion", mock.Anything, mock.Anything)
+}
+
+func TestCachedConfigService_ServesStaleOnError(t *testing.T) {
+	var refreshes atomic.Int32
+	mocks := newCachedServiceMocks()
+	mocks.platformVersions.On("GetPlatformVersion", mock.Anything, "android").
+		Run(func(mock.Arguments) { refreshes.Add(1) }).
+		Return(nil, errors.New("database is unavailable"))
+	mocks.expectResources()
+	memoryCache := cache.NewMemoryCache(10)
+
+	service := NewCachedConfigService(
+		mocks.configService(),
+		memoryCache,
+		time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	service.EnableServeStale(time.Hour)
+
+	params := ClientParams{Platform: "android", AppVersion: "14.8.447"}
+	cacheConfiguration(t, memoryCache, params, &Configuration{Assets: Resource{Version: "14.8.447"}}, time.Now().Add(-time.Second))
+
+	config, err := service.GetConfiguration(context.Background(), params)
+	require.NoError(t, err)
+	assert.True(t, config.Stale)
+	assert.Equal(t, "14.8.447", config.Assets.Version)
+
+	// The background refresh fails and the stale configuration stays in cache
+	assert.Eventually(t, func() bool {
+		return refreshes.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	config, err = service.GetConfiguration(context.Background(), params)
+	require.NoError(t, err)
+	assert.True(t, config.Stale)
+}
+
+func TestCachedConfigService_RemovesStaleNotFound(t *testing.T) {
+	mocks := newCachedServiceMocks()
+	mocks.platformVersions.On("GetPlatformVersion", mock.Anything, "android").
+		Return(nil, sql.ErrNoRows)
+	mocks.expectResources()
+	memoryCache := cache.NewMemoryCache(10)
+
+	service := NewCachedConfigService(
+		mocks.configService(),
+		memoryCache,
+		time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	service.EnableServeStale(time.Hour)
+
+	params := ClientParams{Platform: "android", AppVersion: "14.8.447"}
+	cacheConfiguration(t, memoryCache, params, &Configuration{}, time.Now().Add(-time.Second))
+
+	_, err := service.GetConfiguration(context.Background(), params)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, ok := memoryCache.Get(context.Background(), resolutionCacheKey(params))
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCachedConfigService_SharesConfigurationAcrossPatchVersions(t *testing.T) {
+	mocks := newCachedServiceMocks()
+	mocks.expectSuccess(0)
+	mocks.assets.On("GetCompatibleResource", mock.Anything, "android", "14.9.1").
+		Return(&storage.Resource{Version: "14.8.447", Hash: "abc123"}, nil)
+	mocks.definitions.On("GetCompatibleResource", mock.Anything, "android", "14.9.1").
+		Return(&storage.Resource{Version: "14.8.98", Hash: "def456"}, nil)
+	memoryCache := cache.NewMemoryCache(10)
+
+	service := NewCachedConfigService(
+		mocks.configService(),
+		memoryCache,
+		time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	ctx := context.Background()
+	_, err := service.GetConfiguration(ctx, ClientParams{Platform: "android", AppVersion: "14.8.447"})
+	require.NoError(t, err)
+
+	// Another patch of the same MAJOR.MINOR is served from the resolution cache
+	config, err := service.GetConfiguration(ctx, ClientParams{Platform: "android", AppVersion: "14.8.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "14.8.447", config.Assets.Version)
+	mocks.platformVersions.AssertNumberOfCalls(t, "GetPlatformVersion", 1)
+
+	// Another MINOR is resolved again but reuses the assembled configuration
+	config, err = service.GetConfiguration(ctx, ClientParams{Platform: "android", AppVersion: "14.9.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "14.8.98", config.Definitions.Version)
+	mocks.platformVersions.AssertNumberOfCalls(t, "GetPlatformVersion", 2)
+	mocks.assetURLs.AssertNumberOfCalls(t, "ListURLs", 1)
+
+	// One resolution per MAJOR.MINOR and one configuration
+	assert.Equal(t, 3, memoryCache.Len())
+}
+
+func TestCachedConfigService_InvalidateAssets(t *testing.T) {
+	memoryCache := cache.NewMemoryCache(10)
+	service := NewCachedConfigService(
+		newCachedServiceMocks().configService(),
+		memoryCache,
+		time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	ctx := context.Background()
+	fresh := time.Now().Add(time.Minute)
+	oldParams := ClientParams{Platform: "android", AppVersion: "13.2.0"}
+	newParams := ClientParams{Platform: "android", AppVersion: "14.8.447"}
+	iosParams := ClientParams{Platform: "ios", AppVersion: "14.8.447"}
+	cacheConfiguration(t, memoryCache, oldParams, &Configuration{Assets: Resource{Version: "13.2.528"}}, fresh)
+	cacheConfiguration(t, memoryCache, newParams, &Configuration{Assets: Resource{Version: "14.8.447"}}, fresh)
+	cacheConfiguration(t, memoryCache, iosParams, &Configuration{Assets: Resource{Version: "14.8.447"}}, fresh)
+
+	require.NoError(t, service.InvalidateAssets(ctx, "android", []string{"14.8.447"}))
+
+	// Resolutions of the platform are dropped
+	_, ok := memoryCache.Get(ctx, resolutionCacheKey(oldParams))
+	assert.False(t, ok)
+	_, ok = memoryCache.Get(ctx, resolutionCacheKey(newParams))
+	assert.False(t, ok)
+
+	// Only configurations of the changed assets version are dropped
+	_, ok = memoryCache.Get(ctx, "config:android:13.2.528:")
+	assert.True(t, ok)
+	_, ok = memoryCache.Get(ctx, "config:android:14.8.447:")
+	assert.False(t, ok)
+
+	// Other platforms are not affected
+	_, _, ok = service.getCached(ctx, resolutionCacheKey(iosParams))
+	assert.True(t, ok)
+}
+
+func TestResolutionCacheKey(t *testing.T) {
+	assert.Equal(t, "resolve:android:14.8::", resolutionCacheKey(ClientParams{Platform: "android", AppVersion: "14.8.447"}))
+	assert.Equal(t, "resolve:android:14.8::", resolutionCacheKey(ClientParams{Platform: "android", AppVersion: "14.8.1"}))
+	assert.Equal(t, "resolve:ios:14.8:14.8.447:14.8.98", resolutionCacheKey(ClientParams{
+		Platform:           "ios",
+		AppVersion:         "14.8.2",
+		AssetsVersion:      "14.8.447",
+		DefinitionsVersion: "14.8.98",
+	}))
+}
+
+func TestCachedConfigService_RecordsCacheResults(t *testing.T) {
+	mocks := newCachedServiceMocks()
+	mocks.expectSuccess(0)
+
+	reader := sdkmetric.NewManualReader()
+	service := NewCachedConfigService(
+		mocks.configService(),
+		cache.NewMemoryCache(10),
+		time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	require.NoError(t, service.EnableMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")))
+
+	params := ClientParams{Platform: "android", AppVersion: "14.8.447"}
+	for range 3 {
+		_, err := service.GetConfiguration(context.Background(), params)
+		require.NoError(t, err)
+	}
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+
+	results := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != "config.cache.results" {
+				continue
+			}
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				result, _ := point.Attributes.Value("result")
+				results[result.AsString()] += point.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{cacheResultMiss: 1, cacheResultHit: 2}, results)
+}
+
+func TestCachedConfigService_RecordsSpans(t *testing.T) {
+	mocks := newCachedServiceMocks()
+	mocks.expectSuccess(0)
+
+	exporter := tracetest.NewInMemoryExporter()
+	service := NewCachedConfigService(
+		mocks.configService(),
+		cache.NewMemoryCache(10),
+		time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	service.EnableTracing(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	_, err := service.GetConfiguration(context.Background(), ClientParams{Platform: "android", AppVersion: "14.8.447"})
+	require.NoError(t, err)
+
+	counts := map[string]int{}
+	var root *tracetest.SpanStub
+	spans := exporter.GetSpans()
+	for i, span := range spans {
+		counts[span.Name]++
+		if span.Name == "CachedConfigService.GetConfiguration" {
+			root = &spans[i]
+		}
+	}
+	// The missing resolution is looked up before and inside the coalesced call, then the
+	// configuration of the resolved resources is looked up and both entries are stored
+	assert.Equal(t, map[string]int{"CachedConfigService.GetConfiguration": 1, "cache.get": 3, "cache.set": 2}, counts)
+
+	require.NotNil(t, root)
+	assert.Contains(t, root.Attributes, attribute.String("cache.result", cacheResultMiss))
+	for _, span := range spans {
+		assert.Equal(t, root.SpanContext.TraceID(), span.SpanContext.TraceID(), span.Name)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sw-config-api/internal/storage"
 
 	"github.com/Masterminds/semver"
+	"golang.org/x/sync/errgroup"
 )
 
 // ClientParams represents parameters for configuration retrieval from client
@@ -53,9 +54,139 @@ const (
 	notificationsEntryPointKey = "notifications"
 )
 
-// GetConfiguration retrieves configuration for the given parameters
+// Resolution identifies the resources a client gets. It depends only on the platform,
+// MAJOR and MINOR of the app version and explicitly requested resource versions.
+type Resolution struct {
+	Platform        string                  `json:"platform"`
+	PlatformVersion storage.PlatformVersion `json:"platform_version"`
+	Asset           storage.Resource        `json:"asset"`
+	Definition      storage.Resource        `json:"definition"`
+}
+
+// GetConfiguration retrieves configuration for the given parameters.
+// Resources are resolved while the data shared by all platforms is loaded, a failure
+// of either cancels the other, except for the platform lookup that is always waited for.
 func (s *ConfigService) GetConfiguration(ctx context.Context, params ClientParams) (*Configuration, error) {
-	// Get platform version information
+	var (
+		resolution  *Resolution
+		shared      *sharedData
+		resolveErr  error
+		sharedErr   error
+		group, gctx = errgroup.WithContext(ctx)
+	)
+	group.Go(func() error {
+		// A failed shared data lookup cancels the resource lookups, but not the platform one,
+		// so that an unknown platform is reported as such
+		resolution, resolveErr = s.resolve(ctx, gctx, params)
+		return resolveErr
+	})
+	group.Go(func() error {
+		shared, sharedErr = s.getSharedData(gctx)
+		return sharedErr
+	})
+	if err := group.Wait(); err != nil {
+		err = firstError(ctx, err, resolveErr, sharedErr)
+		if (resolution != nil || platformKnown(resolveErr)) && !platformKnown(err) {
+			err = &knownPlatformError{err: err}
+		}
+		return nil, err
+	}
+
+	return buildConfiguration(resolution, shared), nil
+}
+
+// Resolve selects platform version, assets and definitions for the given parameters.
+// The lookups run concurrently. A failure cancels the resource lookups, but the platform
+// lookup is always waited for, so that an unknown platform is reported as such.
+func (s *ConfigService) Resolve(ctx context.Context, params ClientParams) (*Resolution, error) {
+	return s.resolve(ctx, ctx, params)
+}
+
+// resolve is Resolve with resource lookups running on resourceCtx, which callers
+// may cancel without canceling the platform lookup
+func (s *ConfigService) resolve(ctx, resourceCtx context.Context, params ClientParams) (*Resolution, error) {
+	var (
+		platformVersion *storage.PlatformVersion
+		asset           *storage.Resource
+		definition      *storage.Resource
+		platformErr     error
+		assetErr        error
+		definitionErr   error
+		group, gctx     = errgroup.WithContext(resourceCtx)
+	)
+	group.Go(func() error {
+		// Not canceled by failed resource lookups, it stops only with ctx
+		platformVersion, platformErr = s.getPlatformVersion(ctx, params)
+		return platformErr
+	})
+	group.Go(func() error {
+		asset, assetErr = s.getAsset(gctx, params)
+		return assetErr
+	})
+	group.Go(func() error {
+		definition, definitionErr = s.getDefinition(gctx, params)
+		return definitionErr
+	})
+	if err := group.Wait(); err != nil {
+		err = firstError(ctx, err, platformErr, assetErr, definitionErr)
+		if platformErr == nil {
+			err = &knownPlatformError{err: err}
+		}
+		return nil, err
+	}
+
+	return &Resolution{
+		Platform:        params.Platform,
+		PlatformVersion: *platformVersion,
+		Asset:           *asset,
+		Definition:      *definition,
+	}, nil
+}
+
+// Build assembles configuration for the resolved resources
+func (s *ConfigService) Build(ctx context.Context, resolution *Resolution) (*Configuration, error) {
+	shared, err := s.getSharedData(ctx)
+	if err != nil {
+		return nil, &knownPlatformError{err: err} // The platform was found by Resolve
+	}
+	return buildConfiguration(resolution, shared), nil
+}
+
+// knownPlatformError wraps errors of requests whose platform was found in storage.
+// Other errors may come from any platform a client sends, so they are not attributed to it.
+type knownPlatformError struct {
+	err error
+}
+
+func (e *knownPlatformError) Error() string {
+	return e.err.Error()
+}
+
+func (e *knownPlatformError) Unwrap() error {
+	return e.err
+}
+
+// platformKnown reports whether the error was returned after the platform was found in storage
+func platformKnown(err error) bool {
+	var knownErr *knownPlatformError
+	return errors.As(err, &knownErr)
+}
+
+// firstError picks the error to report after concurrent lookups fail. Errors are checked in
+// lookup order, so the error of an earlier lookup wins if it has completed, e.g. an unknown
+// platform in Resolve. Lookups canceled because another one failed are skipped.
+func firstError(ctx context.Context, groupErr error, errs ...error) error {
+	for _, err := range errs {
+		if err == nil || (errors.Is(err, context.Canceled) && ctx.Err() == nil) {
+			continue
+		}
+		return err
+	}
+	return groupErr
+}
+
+// getPlatformVersion gets platform version information
+func (s *ConfigService) getPlatformVersion(ctx context.Context, params ClientParams) (*storage.PlatformVersion, error) {
 	platformVersion, err := s.platformVersionRepository.GetPlatformVersion(ctx, params.Platform)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -65,14 +196,14 @@ func (s *ConfigService) GetConfiguration(ctx context.Context, params ClientParam
 		}
 		return nil, err // Return original error for database issues
 	}
+	return platformVersion, nil
+}
 
-	var asset *storage.Resource
-	var definition *storage.Resource
-
-	// Handle assets version selection
+// getAsset handles assets version selection
+func (s *ConfigService) getAsset(ctx context.Context, params ClientParams) (*storage.Resource, error) {
 	if params.AssetsVersion != "" {
 		// Client explicitly specified assetsVersion - try to get exact version
-		asset, err = s.assetRepository.GetResource(ctx, params.Platform, params.AssetsVersion)
+		asset, err := s.assetRepository.GetResource(ctx, params.Platform, params.AssetsVersion)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return nil, fmt.Errorf("specified assets version %s not found: %w", params.AssetsVersion, &NotFoundError{
@@ -89,24 +220,28 @@ func (s *ConfigService) GetConfiguration(ctx context.Context, params ClientParam
 				AppVersion: params.AppVersion,
 			})
 		}
-	} else {
-		// No explicit assetsVersion - find compatible version
-		asset, err = s.assetRepository.GetCompatibleResource(ctx, params.Platform, params.AppVersion)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return nil, fmt.Errorf("no compatible assets version found: %w", &NotFoundError{
-					Platform:   params.Platform,
-					AppVersion: params.AppVersion,
-				})
-			}
-			return nil, err // Return original error for database issues
+		return asset, nil
+	}
+
+	// No explicit assetsVersion - find compatible version
+	asset, err := s.assetRepository.GetCompatibleResource(ctx, params.Platform, params.AppVersion)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no compatible assets version found: %w", &NotFoundError{
+				Platform:   params.Platform,
+				AppVersion: params.AppVersion,
+			})
 		}
+		return nil, err // Return original error for database issues
 	}
+	return asset, nil
+}
 
-	// Handle definitions version selection
+// getDefinition handles definitions version selection
+func (s *ConfigService) getDefinition(ctx context.Context, params ClientParams) (*storage.Resource, error) {
 	if params.DefinitionsVersion != "" {
 		// Client explicitly specified definitionsVersion - try to get exact version
-		definition, err = s.definitionRepository.GetResource(ctx, params.Platform, params.DefinitionsVersion)
+		definition, err := s.definitionRepository.GetResource(ctx, params.Platform, params.DefinitionsVersion)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return nil, fmt.Errorf("specified definitions version %s not found: %w", params.DefinitionsVersion, &NotFoundError{
@@ -123,63 +258,93 @@ func (s *ConfigService) GetConfiguration(ctx context.Context, params ClientParam
 				AppVersion: params.AppVersion,
 			})
 		}
-	} else {
-		// No explicit definitionsVersion - find compatible version
-		definition, err = s.definitionRepository.GetCompatibleResource(ctx, params.Platform, params.AppVersion)
-		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return nil, fmt.Errorf("no compatible definitions version found: %w", &NotFoundError{
-					Platform:   params.Platform,
-					AppVersion: params.AppVersion,
-				})
-			}
-			return nil, err // Return original error for database issues
-		}
+		return definition, nil
 	}
 
-	// Get asset URLs
-	assetURLs, err := s.assetURLRepository.ListURLs(ctx)
+	// No explicit definitionsVersion - find compatible version
+	definition, err := s.definitionRepository.GetCompatibleResource(ctx, params.Platform, params.AppVersion)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get asset URLs: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("no compatible definitions version found: %w", &NotFoundError{
+				Platform:   params.Platform,
+				AppVersion: params.AppVersion,
+			})
+		}
+		return nil, err // Return original error for database issues
 	}
+	return definition, nil
+}
 
-	// Get definition URLs
-	definitionURLs, err := s.definitionURLRepository.ListURLs(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get definition URLs: %w", err)
-	}
+// sharedData is the part of configuration shared by all platforms
+type sharedData struct {
+	assetURLs      []string
+	definitionURLs []string
+	entryPoints    map[string]string
+}
 
-	// Get entry points
-	entryPoints, err := s.entryPointRepository.Get(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get entry points: %w", err)
+// getSharedData loads URLs and entry points concurrently
+func (s *ConfigService) getSharedData(ctx context.Context) (*sharedData, error) {
+	var (
+		shared            sharedData
+		assetURLsErr      error
+		definitionURLsErr error
+		entryPointsErr    error
+		group, gctx       = errgroup.WithContext(ctx)
+	)
+	group.Go(func() error {
+		// Get asset URLs
+		shared.assetURLs, assetURLsErr = s.assetURLRepository.ListURLs(gctx)
+		if assetURLsErr != nil {
+			assetURLsErr = fmt.Errorf("failed to get asset URLs: %w", assetURLsErr)
+		}
+		return assetURLsErr
+	})
+	group.Go(func() error {
+		// Get definition URLs
+		shared.definitionURLs, definitionURLsErr = s.definitionURLRepository.ListURLs(gctx)
+		if definitionURLsErr != nil {
+			definitionURLsErr = fmt.Errorf("failed to get definition URLs: %w", definitionURLsErr)
+		}
+		return definitionURLsErr
+	})
+	group.Go(func() error {
+		// Get entry points
+		shared.entryPoints, entryPointsErr = s.entryPointRepository.Get(gctx)
+		if entryPointsErr != nil {
+			entryPointsErr = fmt.Errorf("failed to get entry points: %w", entryPointsErr)
+		}
+		return entryPointsErr
+	})
+	if err := group.Wait(); err != nil {
+		return nil, firstError(ctx, err, assetURLsErr, definitionURLsErr, entryPointsErr)
 	}
+	return &shared, nil
+}
 
-	// Build configuration
-	config := &Configuration{
+// buildConfiguration assembles configuration from the resolved resources and shared data
+func buildConfiguration(resolution *Resolution, shared *sharedData) *Configuration {
+	return &Configuration{
 		Version: VersionInfo{
-			Required: platformVersion.RequiredVersion,
-			Store:    platformVersion.StoreVersion,
+			Required: resolution.PlatformVersion.RequiredVersion,
+			Store:    resolution.PlatformVersion.StoreVersion,
 		},
 		BackendEntryPoint: BackendService{
-			JsonRpcUrl: entryPoints[backendEntryPointKey],
+			JsonRpcUrl: shared.entryPoints[backendEntryPointKey],
 		},
 		Assets: Resource{
-			Version: asset.Version,
-			Hash:    asset.Hash,
-			Urls:    assetURLs,
+			Version: resolution.Asset.Version,
+			Hash:    resolution.Asset.Hash,
+			Urls:    shared.assetURLs,
 		},
 		Definitions: Resource{
-			Version: definition.Version,
-			Hash:    definition.Hash,
-			Urls:    definitionURLs,
+			Version: resolution.Definition.Version,
+			Hash:    resolution.Definition.Hash,
+			Urls:    shared.definitionURLs,
 		},
 		Notifications: BackendService{
-			JsonRpcUrl: entryPoints[notificationsEntryPointKey],
+			JsonRpcUrl: shared.entryPoints[notificationsEntryPointKey],
 		},
 	}
-
-	return config, nil
 }
 
 // isAssetsCompatible checks if assets version is compatible with app version
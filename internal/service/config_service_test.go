@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
 	serviceErrors "sw-config-api/internal/errors"
 	"sw-config-api/internal/storage"
@@ -59,6 +62,21 @@ func (m *MockPlatformVersionRepository) GetPlatformVersion(ctx context.Context,
 	return args.Get(0).(*storage.PlatformVersion), args.Error(1)
 }
 
+// slowPlatformVersionRepository finds no platforms after the delay. Like a database query,
+// it stops with an error when the context is canceled, which the mocks do not.
+type slowPlatformVersionRepository struct {
+	delay time.Duration
+}
+
+func (r slowPlatformVersionRepository) GetPlatformVersion(ctx context.Context, platform string) (*storage.PlatformVersion, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(r.delay):
+		return nil, sql.ErrNoRows
+	}
+}
+
 type MockEntryPointRepository struct {
 	mock.Mock
 }
@@ -71,6 +89,14 @@ func (m *MockEntryPointRepository) Get(ctx context.Context) (map[string]string,
 	return args.Get(0).(map[string]string), args.Error(1)
 }
 
+// allowSharedLookups mocks lookups that run concurrently with a failing one.
+// They may be skipped or canceled, so the calls are optional.
+func allowSharedLookups(assetURLRepo, definitionURLRepo *MockURLRepo, entryPointRepo *MockEntryPointRepository) {
+	assetURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/assets"}, nil).Maybe()
+	definitionURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/definitions"}, nil).Maybe()
+	entryPointRepo.On("Get", mock.Anything).Return(map[string]string{}, nil).Maybe()
+}
+
 func TestConfigService_GetConfiguration_Success(t *testing.T) {
 	// Arrange
 	ctx := context.Background()
@@ -97,29 +123,29 @@ func TestConfigService_GetConfiguration_Success(t *testing.T) {
 	}
 
 	// Mock platform version
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "android").Return(&storage.PlatformVersion{
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
 		RequiredVersion: "13.6.0",
 		StoreVersion:    "13.6.956",
 	}, nil)
 
 	// Mock assets
-	mockAssetRepo.On("GetCompatibleResource", ctx, "android", "13.6.956").Return(&storage.Resource{
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
 		Version: "13.6.956",
 		Hash:    "abc123",
 	}, nil)
 
 	// Mock definitions
-	mockDefinitionRepo.On("GetCompatibleResource", ctx, "android", "13.6.956").Return(&storage.Resource{
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
 		Version: "13.6.956",
 		Hash:    "def456",
 	}, nil)
 
 	// Mock URLs
-	mockAssetURLRepo.On("ListURLs", ctx).Return([]string{"https://cdn.example.com/assets"}, nil)
-	mockDefinitionURLRepo.On("ListURLs", ctx).Return([]string{"https://cdn.example.com/definitions"}, nil)
+	mockAssetURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/assets"}, nil)
+	mockDefinitionURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/definitions"}, nil)
 
 	// Mock entry points
-	mockEntryPointRepo.On("Get", ctx).Return(map[string]string{
+	mockEntryPointRepo.On("Get", mock.Anything).Return(map[string]string{
 		"backend_entry_point": "api.application.com/jsonrpc/v2",
 		"notifications":       "notifications.application.com/jsonrpc/v1",
 	}, nil)
@@ -177,29 +203,29 @@ func TestConfigService_GetConfiguration_WithExplicitVersions(t *testing.T) {
 	}
 
 	// Mock platform version
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "android").Return(&storage.PlatformVersion{
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
 		RequiredVersion: "13.6.0",
 		StoreVersion:    "13.6.956",
 	}, nil)
 
 	// Mock assets with explicit version
-	mockAssetRepo.On("GetResource", ctx, "android", "13.6.955").Return(&storage.Resource{
+	mockAssetRepo.On("GetResource", mock.Anything, "android", "13.6.955").Return(&storage.Resource{
 		Version: "13.6.955",
 		Hash:    "abc123",
 	}, nil)
 
 	// Mock definitions with explicit version
-	mockDefinitionRepo.On("GetResource", ctx, "android", "13.6.954").Return(&storage.Resource{
+	mockDefinitionRepo.On("GetResource", mock.Anything, "android", "13.6.954").Return(&storage.Resource{
 		Version: "13.6.954",
 		Hash:    "def456",
 	}, nil)
 
 	// Mock URLs
-	mockAssetURLRepo.On("ListURLs", ctx).Return([]string{"https://cdn.example.com/assets"}, nil)
-	mockDefinitionURLRepo.On("ListURLs", ctx).Return([]string{"https://cdn.example.com/definitions"}, nil)
+	mockAssetURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/assets"}, nil)
+	mockDefinitionURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/definitions"}, nil)
 
 	// Mock entry points
-	mockEntryPointRepo.On("Get", ctx).Return(map[string]string{
+	mockEntryPointRepo.On("Get", mock.Anything).Return(map[string]string{
 		"backend_entry_point": "api.application.com/jsonrpc/v2",
 		"notifications":       "notifications.application.com/jsonrpc/v1",
 	}, nil)
@@ -243,7 +269,12 @@ func TestConfigService_GetConfiguration_PlatformNotFound(t *testing.T) {
 	}
 
 	// Mock platform version not found
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "unknown").Return(nil, sql.ErrNoRows)
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "unknown").Return(nil, sql.ErrNoRows)
+
+	// Lookups running concurrently with the failing one
+	allowSharedLookups(mockAssetURLRepo, mockDefinitionURLRepo, mockEntryPointRepo)
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "unknown", "13.6.956").Return(nil, sql.ErrNoRows).Maybe()
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "unknown", "13.6.956").Return(nil, sql.ErrNoRows).Maybe()
 
 	// Act
 	config, err := service.GetConfiguration(ctx, params)
@@ -285,13 +316,20 @@ func TestConfigService_GetConfiguration_AssetsNotFound(t *testing.T) {
 	}
 
 	// Mock platform version
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "android").Return(&storage.PlatformVersion{
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
 		RequiredVersion: "13.6.0",
 		StoreVersion:    "13.6.956",
 	}, nil)
 
 	// Mock assets not found
-	mockAssetRepo.On("GetCompatibleResource", ctx, "android", "13.6.956").Return(nil, sql.ErrNoRows)
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(nil, sql.ErrNoRows)
+
+	// Lookups running concurrently with the failing one
+	allowSharedLookups(mockAssetURLRepo, mockDefinitionURLRepo, mockEntryPointRepo)
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
+		Version: "13.6.956",
+		Hash:    "def456",
+	}, nil).Maybe()
 
 	// Act
 	config, err := service.GetConfiguration(ctx, params)
@@ -335,19 +373,22 @@ func TestConfigService_GetConfiguration_DefinitionsNotFound(t *testing.T) {
 	}
 
 	// Mock platform version
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "android").Return(&storage.PlatformVersion{
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
 		RequiredVersion: "13.6.0",
 		StoreVersion:    "13.6.956",
 	}, nil)
 
 	// Mock assets found
-	mockAssetRepo.On("GetCompatibleResource", ctx, "android", "13.6.956").Return(&storage.Resource{
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
 		Version: "13.6.956",
 		Hash:    "abc123",
 	}, nil)
 
 	// Mock definitions not found
-	mockDefinitionRepo.On("GetCompatibleResource", ctx, "android", "13.6.956").Return(nil, sql.ErrNoRows)
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(nil, sql.ErrNoRows)
+
+	// Lookups running concurrently with the failing one
+	allowSharedLookups(mockAssetURLRepo, mockDefinitionURLRepo, mockEntryPointRepo)
 
 	// Act
 	config, err := service.GetConfiguration(ctx, params)
@@ -393,17 +434,24 @@ func TestConfigService_GetConfiguration_IncompatibleAssetsVersion(t *testing.T)
 	}
 
 	// Mock platform version
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "android").Return(&storage.PlatformVersion{
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
 		RequiredVersion: "13.6.0",
 		StoreVersion:    "13.6.956",
 	}, nil)
 
 	// Mock assets with incompatible version
-	mockAssetRepo.On("GetResource", ctx, "android", "14.0.0").Return(&storage.Resource{
+	mockAssetRepo.On("GetResource", mock.Anything, "android", "14.0.0").Return(&storage.Resource{
 		Version: "14.0.0",
 		Hash:    "abc123",
 	}, nil)
 
+	// Lookups running concurrently with the failing one
+	allowSharedLookups(mockAssetURLRepo, mockDefinitionURLRepo, mockEntryPointRepo)
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
+		Version: "13.6.956",
+		Hash:    "def456",
+	}, nil).Maybe()
+
 	// Act
 	config, err := service.GetConfiguration(ctx, params)
 
@@ -443,23 +491,26 @@ func TestConfigService_GetConfiguration_IncompatibleDefinitionsVersion(t *testin
 	}
 
 	// Mock platform version
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "android").Return(&storage.PlatformVersion{
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
 		RequiredVersion: "13.6.0",
 		StoreVersion:    "13.6.956",
 	}, nil)
 
 	// Mock assets
-	mockAssetRepo.On("GetCompatibleResource", ctx, "android", "13.6.956").Return(&storage.Resource{
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
 		Version: "13.6.956",
 		Hash:    "abc123",
 	}, nil)
 
 	// Mock definitions with incompatible version
-	mockDefinitionRepo.On("GetResource", ctx, "android", "13.5.0").Return(&storage.Resource{
+	mockDefinitionRepo.On("GetResource", mock.Anything, "android", "13.5.0").Return(&storage.Resource{
 		Version: "13.5.0",
 		Hash:    "def456",
 	}, nil)
 
+	// Lookups running concurrently with the failing one
+	allowSharedLookups(mockAssetURLRepo, mockDefinitionURLRepo, mockEntryPointRepo)
+
 	// Act
 	config, err := service.GetConfiguration(ctx, params)
 
@@ -499,29 +550,29 @@ func TestConfigService_GetConfiguration_EntryPointsError(t *testing.T) {
 	}
 
 	// Mock platform version
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "android").Return(&storage.PlatformVersion{
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
 		RequiredVersion: "13.6.0",
 		StoreVersion:    "13.6.956",
 	}, nil)
 
 	// Mock assets
-	mockAssetRepo.On("GetCompatibleResource", ctx, "android", "13.6.956").Return(&storage.Resource{
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
 		Version: "13.6.956",
 		Hash:    "abc123",
 	}, nil)
 
 	// Mock definitions
-	mockDefinitionRepo.On("GetCompatibleResource", ctx, "android", "13.6.956").Return(&storage.Resource{
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
 		Version: "13.6.956",
 		Hash:    "def456",
 	}, nil)
 
 	// Mock URLs
-	mockAssetURLRepo.On("ListURLs", ctx).Return([]string{"https://cdn.example.com/assets"}, nil)
-	mockDefinitionURLRepo.On("ListURLs", ctx).Return([]string{"https://cdn.example.com/definitions"}, nil)
+	mockAssetURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/assets"}, nil)
+	mockDefinitionURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/definitions"}, nil)
 
 	// Mock entry points error
-	mockEntryPointRepo.On("Get", ctx).Return(nil, errors.New("database error"))
+	mockEntryPointRepo.On("Get", mock.Anything).Return(nil, errors.New("database error"))
 
 	// Act
 	config, err := service.GetConfiguration(ctx, params)
@@ -539,6 +590,227 @@ func TestConfigService_GetConfiguration_EntryPointsError(t *testing.T) {
 	mockEntryPointRepo.AssertExpectations(t)
 }
 
+func TestConfigService_GetConfiguration_PartialFailures(t *testing.T) {
+	databaseErr := errors.New("database error")
+
+	tests := []struct {
+		name          string
+		platformErr   error
+		assetsErr     error
+		assetURLsErr  error
+		expectedErr   string
+		expectedFound bool // whether the error maps to NotFoundError
+	}{
+		{
+			name:          "unknown platform is reported over a concurrent database error",
+			platformErr:   sql.ErrNoRows,
+			assetsErr:     databaseErr,
+			expectedErr:   "not found",
+			expectedFound: true,
+		},
+		{
+			name:          "missing assets are reported over a concurrent URL error",
+			assetsErr:     sql.ErrNoRows,
+			assetURLsErr:  databaseErr,
+			expectedErr:   "no compatible assets version found",
+			expectedFound: true,
+		},
+		{
+			name:         "URL error with resolved resources",
+			assetURLsErr: databaseErr,
+			expectedErr:  "failed to get asset URLs: database error",
+		},
+		{
+			name:        "database error of a resource lookup",
+			assetsErr:   databaseErr,
+			expectedErr: "database error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockAssetRepo := &MockResourceRepo{}
+			mockDefinitionRepo := &MockResourceRepo{}
+			mockAssetURLRepo := &MockURLRepo{}
+			mockDefinitionURLRepo := &MockURLRepo{}
+			mockPlatformVersionRepo := &MockPlatformVersionRepository{}
+			mockEntryPointRepo := &MockEntryPointRepository{}
+
+			service := NewConfigService(
+				mockAssetRepo,
+				mockDefinitionRepo,
+				mockAssetURLRepo,
+				mockDefinitionURLRepo,
+				mockPlatformVersionRepo,
+				mockEntryPointRepo,
+			)
+
+			if tt.platformErr != nil {
+				mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(nil, tt.platformErr)
+			} else {
+				mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
+					RequiredVersion: "13.6.0",
+					StoreVersion:    "13.6.956",
+				}, nil).Maybe()
+			}
+			if tt.assetsErr != nil {
+				mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(nil, tt.assetsErr).Maybe()
+			} else {
+				mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
+					Version: "13.6.956",
+					Hash:    "abc123",
+				}, nil).Maybe()
+			}
+			mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(&storage.Resource{
+				Version: "13.6.956",
+				Hash:    "def456",
+			}, nil).Maybe()
+			if tt.assetURLsErr != nil {
+				mockAssetURLRepo.On("ListURLs", mock.Anything).Return(nil, tt.assetURLsErr).Maybe()
+			}
+			allowSharedLookups(mockAssetURLRepo, mockDefinitionURLRepo, mockEntryPointRepo)
+
+			// Act
+			config, err := service.GetConfiguration(context.Background(), ClientParams{
+				Platform:   "android",
+				AppVersion: "13.6.956",
+			})
+
+			// Assert
+			require.Error(t, err)
+			assert.Nil(t, config)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			assert.Equal(t, tt.expectedFound, IsNotFoundError(err))
+		})
+	}
+}
+
+func TestConfigService_GetConfiguration_CancelsLookupsOnFailure(t *testing.T) {
+	// Arrange
+	mockAssetRepo := &MockResourceRepo{}
+	mockDefinitionRepo := &MockResourceRepo{}
+	mockAssetURLRepo := &MockURLRepo{}
+	mockDefinitionURLRepo := &MockURLRepo{}
+	mockPlatformVersionRepo := &MockPlatformVersionRepository{}
+	mockEntryPointRepo := &MockEntryPointRepository{}
+
+	service := NewConfigService(
+		mockAssetRepo,
+		mockDefinitionRepo,
+		mockAssetURLRepo,
+		mockDefinitionURLRepo,
+		mockPlatformVersionRepo,
+		mockEntryPointRepo,
+	)
+
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
+		RequiredVersion: "13.6.0",
+		StoreVersion:    "13.6.956",
+	}, nil)
+
+	// Slow definitions lookup that stops when its context is canceled
+	canceled := make(chan struct{})
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+			close(canceled)
+		}).
+		Return(nil, context.Canceled)
+
+	// Failing assets lookup
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(nil, errors.New("database error"))
+	allowSharedLookups(mockAssetURLRepo, mockDefinitionURLRepo, mockEntryPointRepo)
+
+	// Act
+	config, err := service.GetConfiguration(context.Background(), ClientParams{
+		Platform:   "android",
+		AppVersion: "13.6.956",
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, config)
+	assert.EqualError(t, err, "database error")
+	assert.False(t, IsNotFoundError(err))
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("definitions lookup was not canceled")
+	}
+}
+
+func TestConfigService_Resolve_WaitsForPlatformLookup(t *testing.T) {
+	// Arrange
+	mockAssetRepo := &MockResourceRepo{}
+	mockDefinitionRepo := &MockResourceRepo{}
+
+	// Slow lookup of an unknown platform
+	service := NewConfigService(
+		mockAssetRepo,
+		mockDefinitionRepo,
+		&MockURLRepo{},
+		&MockURLRepo{},
+		slowPlatformVersionRepository{delay: 50 * time.Millisecond},
+		&MockEntryPointRepository{},
+	)
+
+	// Assets of an unknown platform are not found right away
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "web", "13.6.956").Return(nil, sql.ErrNoRows)
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "web", "13.6.956").Return(nil, sql.ErrNoRows).Maybe()
+
+	// Act
+	resolution, err := service.Resolve(context.Background(), ClientParams{
+		Platform:   "web",
+		AppVersion: "13.6.956",
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, resolution)
+	assert.EqualError(t, err, "configuration not found for web")
+}
+
+func TestConfigService_GetConfiguration_WaitsForPlatformLookup(t *testing.T) {
+	// Arrange
+	mockAssetRepo := &MockResourceRepo{}
+	mockDefinitionRepo := &MockResourceRepo{}
+	mockAssetURLRepo := &MockURLRepo{}
+	mockDefinitionURLRepo := &MockURLRepo{}
+	mockEntryPointRepo := &MockEntryPointRepository{}
+
+	// Slow lookup of an unknown platform
+	service := NewConfigService(
+		mockAssetRepo,
+		mockDefinitionRepo,
+		mockAssetURLRepo,
+		mockDefinitionURLRepo,
+		slowPlatformVersionRepository{delay: 50 * time.Millisecond},
+		mockEntryPointRepo,
+	)
+
+	// Resource lookups stop when the failed shared data lookup cancels them
+	waitForCancel := func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "web", "13.6.956").Run(waitForCancel).Return(nil, context.Canceled).Maybe()
+	mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "web", "13.6.956").Run(waitForCancel).Return(nil, context.Canceled).Maybe()
+	mockAssetURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/assets"}, nil).Maybe()
+	mockDefinitionURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/definitions"}, nil).Maybe()
+	mockEntryPointRepo.On("Get", mock.Anything).Return(nil, errors.New("database error"))
+
+	// Act
+	config, err := service.GetConfiguration(context.Background(), ClientParams{
+		Platform:   "web",
+		AppVersion: "13.6.956",
+	})
+
+	// Assert
+	require.Error(t, err)
+	assert.Nil(t, config)
+	assert.True(t, IsNotFoundError(err))
+	assert.EqualError(t, err, "configuration not found for web")
+}
+
 func TestConfigService_CompatibilityChecks(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -616,17 +888,17 @@ func TestConfigService_SpecificAssetVersionRequests(t *testing.T) {
 	)
 
 	// Mock platform version
-	mockPlatformVersionRepo.On("GetPlatformVersion", ctx, "android").Return(&storage.PlatformVersion{
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(&storage.PlatformVersion{
 		RequiredVersion: "12.2.423",
 		StoreVersion:    "13.7.556",
 	}, nil)
 
 	// Mock URLs
-	mockAssetURLRepo.On("ListURLs", ctx).Return([]string{"https://cdn.example.com/assets"}, nil)
-	mockDefinitionURLRepo.On("ListURLs", ctx).Return([]string{"https://cdn.example.com/definitions"}, nil)
+	mockAssetURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/assets"}, nil)
+	mockDefinitionURLRepo.On("ListURLs", mock.Anything).Return([]string{"https://cdn.example.com/definitions"}, nil)
 
 	// Mock entry points
-	mockEntryPointRepo.On("Get", ctx).Return(map[string]string{
+	mockEntryPointRepo.On("Get", mock.Anything).Return(map[string]string{
 		"backend_entry_point": "api.application.com/jsonrpc/v2",
 		"notifications":       "notifications.application.com/jsonrpc/v1",
 	}, nil)
@@ -640,13 +912,13 @@ func TestConfigService_SpecificAssetVersionRequests(t *testing.T) {
 		}
 
 		// Mock assets with exact version
-		mockAssetRepo.On("GetResource", ctx, "android", "14.8.447").Return(&storage.Resource{
+		mockAssetRepo.On("GetResource", mock.Anything, "android", "14.8.447").Return(&storage.Resource{
 			Version: "14.8.447",
 			Hash:    "7b49ade9146a11ecbafa1b3c9ed25d1972e1a7c8b2b292e8b3ad1bb599024804",
 		}, nil)
 
 		// Mock definitions with compatible version
-		mockDefinitionRepo.On("GetCompatibleResource", ctx, "android", "14.8.447").Return(&storage.Resource{
+		mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, "android", "14.8.447").Return(&storage.Resource{
 			Version: "14.8.98",
 			Hash:    "def456",
 		}, nil)
@@ -670,7 +942,7 @@ func TestConfigService_SpecificAssetVersionRequests(t *testing.T) {
 		}
 
 		// Mock assets with incompatible version - should return not found
-		mockAssetRepo.On("GetResource", ctx, "android", "13.2.528").Return(nil, sql.ErrNoRows)
+		mockAssetRepo.On("GetResource", mock.Anything, "android", "13.2.528").Return(nil, sql.ErrNoRows)
 
 		// Act
 		config, err := service.GetConfiguration(ctx, params)
@@ -691,13 +963,13 @@ func TestConfigService_SpecificAssetVersionRequests(t *testing.T) {
 		}
 
 		// Mock assets with exact version
-		mockAssetRepo.On("GetResource", ctx, "android", "14.8.447").Return(&storage.Resource{
+		mockAssetRepo.On("GetResource", mock.Anything, "android", "14.8.447").Return(&storage.Resource{
 			Version: "14.8.447",
 			Hash:    "7b49ade9146a11ecbafa1b3c9ed25d1972e1a7c8b2b292e8b3ad1bb599024804",
 		}, nil)
 
 		// Mock definitions with exact version
-		mockDefinitionRepo.On("GetResource", ctx, "android", "14.8.98").Return(&storage.Resource{
+		mockDefinitionRepo.On("GetResource", mock.Anything, "android", "14.8.98").Return(&storage.Resource{
 			Version: "14.8.98",
 			Hash:    "abc123",
 		}, nil)
@@ -719,3 +991,74 @@ func TestConfigService_SpecificAssetVersionRequests(t *testing.T) {
 	mockPlatformVersionRepo.AssertExpectations(t)
 	mockEntryPointRepo.AssertExpectations(t)
 }
+
+func TestResolutionMetrics_LabelsOnlyKnownPlatforms(t *testing.T) {
+	// Arrange
+	mockAssetRepo := &MockResourceRepo{}
+	mockDefinitionRepo := &MockResourceRepo{}
+	mockAssetURLRepo := &MockURLRepo{}
+	mockDefinitionURLRepo := &MockURLRepo{}
+	mockPlatformVersionRepo := &MockPlatformVersionRepository{}
+	mockEntryPointRepo := &MockEntryPointRepository{}
+
+	service := NewConfigService(
+		mockAssetRepo,
+		mockDefinitionRepo,
+		mockAssetURLRepo,
+		mockDefinitionURLRepo,
+		mockPlatformVersionRepo,
+		mockEntryPointRepo,
+	)
+
+	databaseErr := errors.New("database error")
+	platformVersion := &storage.PlatformVersion{RequiredVersion: "13.6.0", StoreVersion: "13.6.956"}
+	definition := &storage.Resource{Version: "13.6.956", Hash: "def456"}
+
+	// android exists without compatible assets, ios exists with a failing assets lookup
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "android").Return(platformVersion, nil)
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "ios").Return(platformVersion, nil)
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "android", "13.6.956").Return(nil, sql.ErrNoRows)
+	mockAssetRepo.On("GetCompatibleResource", mock.Anything, "ios", "13.6.956").Return(nil, databaseErr)
+
+	// Platforms sent by clients that are not confirmed by storage
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "web").Return(nil, sql.ErrNoRows)
+	mockPlatformVersionRepo.On("GetPlatformVersion", mock.Anything, "tv").Return(nil, databaseErr)
+	for _, platform := range []string{"web", "tv"} {
+		mockAssetRepo.On("GetCompatibleResource", mock.Anything, platform, "13.6.956").Return(nil, sql.ErrNoRows).Maybe()
+	}
+	for _, platform := range []string{"android", "ios", "web", "tv"} {
+		mockDefinitionRepo.On("GetCompatibleResource", mock.Anything, platform, "13.6.956").Return(definition, nil).Maybe()
+	}
+	allowSharedLookups(mockAssetURLRepo, mockDefinitionURLRepo, mockEntryPointRepo)
+
+	reader := sdkmetric.NewManualReader()
+	metrics, err := newResolutionMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test"))
+	require.NoError(t, err)
+
+	// Act
+	for _, platform := range []string{"android", "ios", "web", "tv"} {
+		_, err := service.GetConfiguration(context.Background(), ClientParams{Platform: platform, AppVersion: "13.6.956"})
+		metrics.record(context.Background(), platform, err)
+	}
+
+	// Assert
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+
+	counts := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				platform, _ := point.Attributes.Value("platform")
+				code, _ := point.Attributes.Value("code")
+				counts[platform.AsString()+" "+code.AsString()] += point.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"android " + resolutionNotFound:            1,
+		"ios " + resolutionError:                   1,
+		unknownPlatform + " " + resolutionNotFound: 1,
+		unknownPlatform + " " + resolutionError:    1,
+	}, counts)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"sw-config-api/internal/grpcapi"
+	"sw-config-api/internal/logging"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// semVerPattern is the SemVer schema of the OpenAPI spec, ogen validates REST parameters by it
+var semVerPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// GRPCHandler serves the gRPC ConfigService over the same config service as Handler
+type GRPCHandler struct {
+	grpcapi.UnimplementedConfigServiceServer
+
+	configService ConfigServiceInterface
+	logger        *slog.Logger
+	metrics       *resolutionMetrics
+}
+
+// NewGRPCHandler creates a new gRPC handler with config service
+func NewGRPCHandler(configService ConfigServiceInterface, logger *slog.Logger) *GRPCHandler {
+	metrics, _ := newResolutionMetrics(noopMeter) // Instruments of the no-op meter never fail
+
+	return &GRPCHandler{
+		configService: configService,
+		logger:        logger,
+		metrics:       metrics,
+	}
+}
+
+// EnableMetrics reports resolution outcomes by platform to the meter,
+// gRPC requests are counted together with REST ones
+func (h *GRPCHandler) EnableMetrics(meter metric.Meter) error {
+	metrics, err := newResolutionMetrics(meter)
+	if err != nil {
+		return err
+	}
+	h.metrics = metrics
+	return nil
+}
+
+// GetConfig implements ConfigService.GetConfig, the gRPC counterpart of GET /config.
+// Expected failures are returned as statuses, other errors are mapped by middleware.GRPCErrors.
+func (h *GRPCHandler) GetConfig(ctx context.Context, req *grpcapi.GetConfigRequest) (_ *grpcapi.GetConfigResponse, err error) {
+	ctx, span := tracer.Start(ctx, "GRPCHandler.GetConfig", trace.WithAttributes(
+		attribute.String("config.platform", req.GetPlatform()),
+		attribute.String("config.app_version", req.GetAppVersion()),
+	))
+	defer func() {
+		// Invalid requests and missing configurations are expected, as 400 and 404 of Handler
+		if code := status.Code(err); code == codes.InvalidArgument || code == codes.NotFound {
+			endSpan(span, nil)
+			return
+		}
+		endSpan(span, err)
+	}()
+
+	if err := validateGetConfigRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Empty versions are resolved from the app version, as omitted query parameters
+	clientParams := ClientParams{
+		Platform:           req.GetPlatform(),
+		AppVersion:         req.GetAppVersion(),
+		AssetsVersion:      req.GetAssetsVersion(),
+		DefinitionsVersion: req.GetDefinitionsVersion(),
+	}
+
+	config, err := h.configService.GetConfiguration(ctx, clientParams)
+	h.metrics.record(ctx, clientParams.Platform, err)
+	if err != nil {
+		if IsNotFoundError(err) {
+			span.AddEvent("configuration not found")
+
+			// Log the not found error, the request logger carries the request ID
+			logging.FromContext(ctx, h.logger).Warn("Configuration not found",
+				"error", err.Error(),
+				"platform", clientParams.Platform,
+				"appVersion", clientParams.AppVersion,
+			)
+
+			return nil, status.Error(codes.NotFound, "Configuration not found")
+		}
+		return nil, err
+	}
+
+	return &grpcapi.GetConfigResponse{
+		Config: &grpcapi.Config{
+			Version: &grpcapi.Version{
+				Required: config.Version.Required,
+				Store:    config.Version.Store,
+			},
+			BackendEntryPoint: &grpcapi.BackendService{JsonrpcUrl: config.BackendEntryPoint.JsonRpcUrl},
+			Assets: &grpcapi.Resource{
+				Version: config.Assets.Version,
+				Hash:    config.Assets.Hash,
+				Urls:    config.Assets.Urls,
+			},
+			Definitions: &grpcapi.Resource{
+				Version: config.Definitions.Version,
+				Hash:    config.Definitions.Hash,
+				Urls:    config.Definitions.Urls,
+			},
+			Notifications: &grpcapi.BackendService{JsonrpcUrl: config.Notifications.JsonRpcUrl},
+		},
+		Stale: config.Stale,
+	}, nil
+}
+
+// validateGetConfigRequest checks the request as ogen checks query parameters of GET /config.
+// Messages name parameters as the REST API does.
+func validateGetConfigRequest(req *grpcapi.GetConfigRequest) error {
+	if req.GetAppVersion() == "" {
+		return status.Error(codes.InvalidArgument, "Missing required parameter: appVersion")
+	}
+	if req.GetPlatform() == "" {
+		return status.Error(codes.InvalidArgument, "Missing required parameter: platform")
+	}
+
+	versions := []struct{ name, value string }{
+		{"appVersion", req.GetAppVersion()},
+		{"assetsVersion", req.GetAssetsVersion()},
+		{"definitionsVersion", req.GetDefinitionsVersion()},
+	}
+	for _, version := range versions {
+		if version.value != "" && !semVerPattern.MatchString(version.value) {
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid parameter: %s must be in MAJOR.MINOR.PATCH format", version.name))
+		}
+	}
+	return nil
+}
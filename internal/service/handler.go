@@ -5,28 +5,79 @@ import (
 	"log/slog"
 
 	"sw-config-api/internal/api"
+	"sw-config-api/internal/logging"
+	"sw-config-api/internal/requestid"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Handler handles API requests and business logic
 type Handler struct {
 	configService ConfigServiceInterface
 	logger        *slog.Logger
+	metrics       *resolutionMetrics
+
+	// HTTP caching headers of configurations, set only when enabled
+	cacheHeaders      bool
+	cacheControl      string
+	staleCacheControl string
+	vary              string
 }
 
 // NewHandler creates a new handler with config service
 func NewHandler(configService ConfigServiceInterface, logger *slog.Logger) *Handler {
+	metrics, _ := newResolutionMetrics(noopMeter) // Instruments of the no-op meter never fail
+
 	return &Handler{
 		configService: configService,
 		logger:        logger,
+		metrics:       metrics,
 	}
 }
 
+// EnableMetrics reports resolution outcomes by platform to the meter
+func (h *Handler) EnableMetrics(meter metric.Meter) error {
+	metrics, err := newResolutionMetrics(meter)
+	if err != nil {
+		return err
+	}
+	h.metrics = metrics
+	return nil
+}
+
+// EnableCacheHeaders sets Cache-Control and Vary of configurations for CDNs and clients.
+// Stale configurations get staleCacheControl, so that a CDN does not keep serving them
+// after the storage recovers. Configurations are also tagged with Surrogate-Key, so that
+// a CDN can purge them by platform or by resolved assets and definitions versions.
+func (h *Handler) EnableCacheHeaders(cacheControl, staleCacheControl, vary string) {
+	h.cacheHeaders = true
+	h.cacheControl = cacheControl
+	h.staleCacheControl = staleCacheControl
+	h.vary = vary
+}
+
+// surrogateKeys returns tags of the configuration of the platform
+func surrogateKeys(platform string, config *Configuration) string {
+	return "platform:" + platform +
+		" assets:" + platform + ":" + config.Assets.Version +
+		" definitions:" + platform + ":" + config.Definitions.Version
+}
+
 // ConfigGet implements GET /config operation.
 //
 // Get configuration for client.
 //
 // GET /config
-func (h *Handler) ConfigGet(ctx context.Context, params api.ConfigGetParams) (api.ConfigGetRes, error) {
+func (h *Handler) ConfigGet(ctx context.Context, params api.ConfigGetParams) (_ api.ConfigGetRes, err error) {
+	// The span ends before the response is encoded, the server span of ogen includes encoding
+	ctx, span := tracer.Start(ctx, "Handler.ConfigGet", trace.WithAttributes(
+		attribute.String("config.platform", params.Platform),
+		attribute.String("config.app_version", string(params.AppVersion)),
+	))
+	defer func() { endSpan(span, err) }()
+
 	// Create config parameters
 	clientParams := ClientParams{
 		Platform:   params.Platform,
@@ -48,11 +99,14 @@ func (h *Handler) ConfigGet(ctx context.Context, params api.ConfigGetParams) (ap
 
 	// Get configuration from business logic layer
 	config, err := h.configService.GetConfiguration(ctx, clientParams)
+	h.metrics.record(ctx, clientParams.Platform, err)
 	if err != nil {
 		// Check if it's a "not found" error and return appropriate API response
 		if IsNotFoundError(err) {
-			// Log the not found error
-			h.logger.Warn("Configuration not found",
+			span.AddEvent("configuration not found")
+
+			// Log the not found error, the request logger carries the request ID
+			logging.FromContext(ctx, h.logger).Warn("Configuration not found",
 				"error", err.Error(),
 				"platform", clientParams.Platform,
 				"appVersion", clientParams.AppVersion,
@@ -60,8 +114,9 @@ func (h *Handler) ConfigGet(ctx context.Context, params api.ConfigGetParams) (ap
 
 			return &api.ConfigGetNotFound{
 				Error: api.NewOptConfigGetNotFoundError(api.ConfigGetNotFoundError{
-					Code:    api.NewOptInt(404),
-					Message: api.NewOptString("Configuration not found"),
+					Code:      api.NewOptInt(404),
+					Message:   api.NewOptString("Configuration not found"),
+					RequestID: api.NewOptString(requestid.FromContext(ctx)),
 				}),
 			}, nil
 		}
@@ -93,5 +148,28 @@ func (h *Handler) ConfigGet(ctx context.Context, params api.ConfigGetParams) (ap
 		}),
 	}
 
-	return apiConfig, nil
+	response := &api.ConfigHeaders{
+		Response: *apiConfig,
+	}
+
+	// Tell the client that the configuration could not be refreshed recently
+	if config.Stale {
+		response.SetXConfigStale(api.NewOptBool(true))
+	}
+
+	if h.cacheHeaders {
+		cacheControl := h.cacheControl
+		if config.Stale {
+			cacheControl = h.staleCacheControl
+		}
+		if cacheControl != "" {
+			response.SetCacheControl(api.NewOptString(cacheControl))
+		}
+		if h.vary != "" {
+			response.SetVary(api.NewOptString(h.vary))
+		}
+		response.SetSurrogateKey(api.NewOptString(surrogateKeys(clientParams.Platform, config)))
+	}
+
+	return response, nil
 }
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sw-config-api/internal/api"
+	"sw-config-api/internal/requestid"
+)
+
+// configServiceStub returns a fixed configuration or error
+type configServiceStub struct {
+	config *Configuration
+	err    error
+}
+
+func (s configServiceStub) GetConfiguration(context.Context, ClientParams) (*Configuration, error) {
+	return s.config, s.err
+}
+
+func testConfiguration(stale bool) *Configuration {
+	return &Configuration{
+		Version:     VersionInfo{Required: "14.0.0", Store: "14.8.447"},
+		Assets:      Resource{Version: "14.8.447", Hash: "abc123"},
+		Definitions: Resource{Version: "14.8.98", Hash: "def456"},
+		Stale:       stale,
+	}
+}
+
+var testConfigGetParams = api.ConfigGetParams{AppVersion: "14.8.447", Platform: "android"}
+
+func TestHandler_ConfigGet_CacheHeaders(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name         string
+		enabled      bool
+		stale        bool
+		cacheControl string
+		vary         string
+		surrogateKey string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:         "fresh configuration",
+			enabled:      true,
+			cacheControl: "public, max-age=60",
+			vary:         "X-Device-ID",
+			surrogateKey: "platform:android assets:android:14.8.447 definitions:android:14.8.98",
+		},
+		{
+			name:         "stale configuration is not cached for max-age",
+			enabled:      true,
+			stale:        true,
+			cacheControl: "no-cache",
+			vary:         "X-Device-ID",
+			surrogateKey: "platform:android assets:android:14.8.447 definitions:android:14.8.98",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(configServiceStub{config: testConfiguration(tt.stale)}, logger)
+			if tt.enabled {
+				handler.EnableCacheHeaders("public, max-age=60", "no-cache", "X-Device-ID")
+			}
+
+			res, err := handler.ConfigGet(context.Background(), testConfigGetParams)
+			require.NoError(t, err)
+			response, ok := res.(*api.ConfigHeaders)
+			require.True(t, ok)
+
+			assert.Equal(t, tt.cacheControl, response.CacheControl.Or(""))
+			assert.Equal(t, tt.vary, response.Vary.Or(""))
+			assert.Equal(t, tt.surrogateKey, response.SurrogateKey.Or(""))
+			assert.Equal(t, tt.stale, response.XConfigStale.Or(false))
+			assert.Equal(t, "14.8.447", string(response.Response.Assets.Value.Version.Value))
+		})
+	}
+}
+
+func TestHandler_ConfigGet_Errors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := requestid.NewContext(context.Background(), "request-1")
+
+	// Not found responses carry the request ID and no caching headers
+	handler := NewHandler(configServiceStub{err: &NotFoundError{Platform: "android"}}, logger)
+	handler.EnableCacheHeaders("public, max-age=60", "no-cache", "")
+
+	res, err := handler.ConfigGet(ctx, testConfigGetParams)
+	require.NoError(t, err)
+	notFound, ok := res.(*api.ConfigGetNotFound)
+	require.True(t, ok)
+	assert.Equal(t, 404, notFound.Error.Value.Code.Value)
+	assert.Equal(t, "request-1", notFound.Error.Value.RequestID.Value)
+
+	// Other errors are written by the error handler
+	databaseErr := errors.New("database error")
+	handler = NewHandler(configServiceStub{err: databaseErr}, logger)
+	handler.EnableCacheHeaders("public, max-age=60", "no-cache", "")
+
+	res, err = handler.ConfigGet(ctx, testConfigGetParams)
+	assert.ErrorIs(t, err, databaseErr)
+	assert.Nil(t, res)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// Results of configuration cache lookups
+const (
+	cacheResultHit   = "hit"
+	cacheResultStale = "stale"
+	cacheResultMiss  = "miss"
+)
+
+// Cache operations reported in error metrics
+const (
+	cacheOperationSet     = "set"
+	cacheOperationLock    = "lock"
+	cacheOperationRefresh = "refresh"
+	cacheOperationDelete  = "delete"
+)
+
+// Outcomes of configuration resolution by response code
+const (
+	resolutionOK       = "200"
+	resolutionNotFound = "404"
+	resolutionError    = "500"
+)
+
+// unknownPlatform replaces platforms that are not confirmed by storage in metric attributes,
+// so that arbitrary client input does not create new time series
+const unknownPlatform = "unknown"
+
+// noopMeter is used until metrics are enabled
+var noopMeter = noop.NewMeterProvider().Meter("")
+
+// cacheMetrics counts configuration cache lookups and failed cache operations
+type cacheMetrics struct {
+	results metric.Int64Counter
+	errors  metric.Int64Counter
+}
+
+func newCacheMetrics(meter metric.Meter) (*cacheMetrics, error) {
+	results, err := meter.Int64Counter(
+		"config.cache.results",
+		metric.WithDescription("Number of configuration cache lookups by result: hit, stale or miss"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	errs, err := meter.Int64Counter(
+		"config.cache.errors",
+		metric.WithDescription("Number of failed configuration cache operations by operation"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cacheMetrics{results: results, errors: errs}, nil
+}
+
+func (m *cacheMetrics) result(ctx context.Context, result string) {
+	m.results.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+func (m *cacheMetrics) error(ctx context.Context, operation string) {
+	m.errors.Add(ctx, 1, metric.WithAttributes(attribute.String("operation", operation)))
+}
+
+// resolutionMetrics counts configuration requests by platform and outcome
+type resolutionMetrics struct {
+	resolutions metric.Int64Counter
+}
+
+func newResolutionMetrics(meter metric.Meter) (*resolutionMetrics, error) {
+	resolutions, err := meter.Int64Counter(
+		"config.resolutions",
+		metric.WithDescription("Number of configuration resolutions by platform and response code"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &resolutionMetrics{resolutions: resolutions}, nil
+}
+
+// record counts the outcome of resolving configuration for the platform
+func (m *resolutionMetrics) record(ctx context.Context, platform string, err error) {
+	code := resolutionOK
+	if err != nil {
+		code = resolutionError
+		if IsNotFoundError(err) {
+			code = resolutionNotFound
+		}
+
+		// Served configurations confirm the platform, failures only when its lookup succeeded
+		if !platformKnown(err) {
+			platform = unknownPlatform
+		}
+	}
+
+	m.resolutions.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("platform", platform),
+		attribute.String("code", code),
+	))
+}
+
+// repositoryMetrics records latency of repository calls
+type repositoryMetrics struct {
+	duration metric.Float64Histogram
+}
+
+func newRepositoryMetrics(meter metric.Meter) (*repositoryMetrics, error) {
+	duration, err := meter.Float64Histogram(
+		"db.query.duration",
+		metric.WithDescription("Duration of repository queries"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &repositoryMetrics{duration: duration}, nil
+}
+
+// observe records the duration of a repository call started at start
+func (m *repositoryMetrics) observe(ctx context.Context, repository, method string, start time.Time, err error) {
+	result := "ok"
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		result = "not_found"
+	case err != nil:
+		result = "error"
+	}
+	m.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("repository", repository),
+		attribute.String("method", method),
+		attribute.String("result", result),
+	))
+}
@@ -7,6 +7,9 @@ type Configuration struct {
 	Assets            Resource
 	Definitions       Resource
 	Notifications     BackendService
+
+	// Stale is set when the configuration is served from cache after its freshness period
+	Stale bool `json:"-"`
 }
 
 // VersionInfo represents version information for a platform
@@ -26,3 +26,8 @@ type PlatformVersionRepository interface {
 type EntryPointRepository interface {
 	Get(ctx context.Context) (map[string]string, error)
 }
+
+// VersionLineRepository lists app version lines used to warm up the cache
+type VersionLineRepository interface {
+	ListVersionLines(ctx context.Context) ([]storage.VersionLine, error)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"sw-config-api/internal/storage"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RepositoryInstrumentation wraps repositories so that the latency of every call is recorded
+type RepositoryInstrumentation struct {
+	metrics *repositoryMetrics
+}
+
+// NewRepositoryInstrumentation creates instrumentation reporting to the meter
+func NewRepositoryInstrumentation(meter metric.Meter) (*RepositoryInstrumentation, error) {
+	metrics, err := newRepositoryMetrics(meter)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryInstrumentation{metrics: metrics}, nil
+}
+
+// ResourceRepo wraps a resource repository, name is reported as the repository attribute
+func (i *RepositoryInstrumentation) ResourceRepo(name string, repo ResourceRepo) ResourceRepo {
+	return &instrumentedResourceRepo{repo: repo, name: name, metrics: i.metrics}
+}
+
+// URLRepo wraps a URL repository, name is reported as the repository attribute
+func (i *RepositoryInstrumentation) URLRepo(name string, repo URLRepo) URLRepo {
+	return &instrumentedURLRepo{repo: repo, name: name, metrics: i.metrics}
+}
+
+// PlatformVersionRepository wraps a platform version repository
+func (i *RepositoryInstrumentation) PlatformVersionRepository(repo PlatformVersionRepository) PlatformVersionRepository {
+	return &instrumentedPlatformVersionRepository{repo: repo, metrics: i.metrics}
+}
+
+// EntryPointRepository wraps an entry point repository
+func (i *RepositoryInstrumentation) EntryPointRepository(repo EntryPointRepository) EntryPointRepository {
+	return &instrumentedEntryPointRepository{repo: repo, metrics: i.metrics}
+}
+
+type instrumentedResourceRepo struct {
+	repo    ResourceRepo
+	name    string
+	metrics *repositoryMetrics
+}
+
+func (r *instrumentedResourceRepo) GetResource(ctx context.Context, platform, version string) (*storage.Resource, error) {
+	start := time.Now()
+	resource, err := r.repo.GetResource(ctx, platform, version)
+	r.metrics.observe(ctx, r.name, "GetResource", start, err)
+	return resource, err
+}
+
+func (r *instrumentedResourceRepo) GetCompatibleResource(ctx context.Context, platform, appVersion string) (*storage.Resource, error) {
+	start := time.Now()
+	resource, err := r.repo.GetCompatibleResource(ctx, platform, appVersion)
+	r.metrics.observe(ctx, r.name, "GetCompatibleResource", start, err)
+	return resource, err
+}
+
+type instrumentedURLRepo struct {
+	repo    URLRepo
+	name    string
+	metrics *repositoryMetrics
+}
+
+func (r *instrumentedURLRepo) ListURLs(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	urls, err := r.repo.ListURLs(ctx)
+	r.metrics.observe(ctx, r.name, "ListURLs", start, err)
+	return urls, err
+}
+
+type instrumentedPlatformVersionRepository struct {
+	repo    PlatformVersionRepository
+	metrics *repositoryMetrics
+}
+
+func (r *instrumentedPlatformVersionRepository) GetPlatformVersion(ctx context.Context, platform string) (*storage.PlatformVersion, error) {
+	start := time.Now()
+	platformVersion, err := r.repo.GetPlatformVersion(ctx, platform)
+	r.metrics.observe(ctx, storage.PlatformVersionsTable, "GetPlatformVersion", start, err)
+	return platformVersion, err
+}
+
+type instrumentedEntryPointRepository struct {
+	repo    EntryPointRepository
+	metrics *repositoryMetrics
+}
+
+func (r *instrumentedEntryPointRepository) Get(ctx context.Context) (map[string]string, error) {
+	start := time.Now()
+	entryPoints, err := r.repo.Get(ctx)
+	r.metrics.observe(ctx, storage.EntryPointsTable, "Get", start, err)
+	return entryPoints, err
+}
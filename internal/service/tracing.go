@@ -0,0 +1,27 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of spans created by the package
+const tracerName = "sw-config-api/service"
+
+// tracer creates spans of request handling and, by default, cache operations with the global tracer provider
+var tracer = otel.Tracer(tracerName)
+
+// endSpan ends the span recording the error. Configurations that are not found are
+// an expected outcome, they are recorded as an event without the error status.
+func endSpan(span trace.Span, err error) {
+	switch {
+	case err == nil:
+	case IsNotFoundError(err):
+		span.AddEvent("configuration not found")
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Warmer pre-populates the configuration cache for every platform and MAJOR.MINOR app version line,
+// so that the first requests after a deploy or a cache flush do not hit the storage
+type Warmer struct {
+	configService *CachedConfigService
+	repository    VersionLineRepository
+	concurrency   int
+	logger        *slog.Logger
+}
+
+// WarmupResult counts version lines processed by a warm-up
+type WarmupResult struct {
+	Warmed  int // Configurations loaded into cache
+	Skipped int // Lines without a compatible configuration, e.g. definitions without assets
+	Failed  int
+}
+
+// NewWarmer creates a warmer loading at most concurrency configurations at a time
+func NewWarmer(configService *CachedConfigService, repository VersionLineRepository, concurrency int, logger *slog.Logger) *Warmer {
+	return &Warmer{
+		configService: configService,
+		repository:    repository,
+		concurrency:   max(concurrency, 1),
+		logger:        logger,
+	}
+}
+
+// Warmup loads configurations of the platforms into cache, empty list warms up all platforms.
+// Failures of single configurations are counted and logged, an error is returned only
+// when the version lines cannot be listed or ctx is done.
+func (w *Warmer) Warmup(ctx context.Context, platforms []string) (WarmupResult, error) {
+	lines, err := w.repository.ListVersionLines(ctx)
+	if err != nil {
+		return WarmupResult{}, fmt.Errorf("failed to list version lines: %w", err)
+	}
+
+	var (
+		mu     sync.Mutex
+		result WarmupResult
+		group  errgroup.Group
+	)
+	group.SetLimit(w.concurrency)
+
+	for _, line := range lines {
+		if len(platforms) > 0 && !slices.Contains(platforms, line.Platform) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		group.Go(func() error {
+			// Resolution depends only on MAJOR.MINOR, so any patch represents the whole line
+			params := ClientParams{
+				Platform:   line.Platform,
+				AppVersion: fmt.Sprintf("%d.%d.0", line.Major, line.Minor),
+			}
+			_, err := w.configService.GetConfiguration(ctx, params)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				result.Warmed++
+			case IsNotFoundError(err):
+				result.Skipped++
+			default:
+				result.Failed++
+				w.logger.Warn("failed to warm up configuration",
+					"error", err.Error(),
+					"platform", params.Platform,
+					"app_version", params.AppVersion,
+				)
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	return result, ctx.Err()
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sw-config-api/internal/cache"
+	"sw-config-api/internal/storage"
+)
+
+// versionLines is a VersionLineRepository returning fixed lines
+type versionLines []storage.VersionLine
+
+func (l versionLines) ListVersionLines(context.Context) ([]storage.VersionLine, error) {
+	return l, nil
+}
+
+func TestWarmer_Warmup(t *testing.T) {
+	mocks := newCachedServiceMocks()
+	mocks.platformVersions.On("GetPlatformVersion", mock.Anything, mock.Anything).
+		Return(&storage.PlatformVersion{RequiredVersion: "13.0.0", StoreVersion: "14.8.447"}, nil)
+	mocks.assets.On("GetCompatibleResource", mock.Anything, "android", mock.Anything).
+		Return(&storage.Resource{Version: "14.8.447", Hash: "abc123"}, nil)
+	mocks.definitions.On("GetCompatibleResource", mock.Anything, "android", "14.8.0").
+		Return(&storage.Resource{Version: "14.8.98", Hash: "def456"}, nil)
+	mocks.definitions.On("GetCompatibleResource", mock.Anything, "android", "14.7.0").
+		Return(nil, sql.ErrNoRows)
+	mocks.assetURLs.On("ListURLs", mock.Anything).Return([]string{"a.cdn.application.com"}, nil)
+	mocks.definitionURLs.On("ListURLs", mock.Anything).Return([]string{"d.cdn.application.com"}, nil)
+	mocks.entryPoints.On("Get", mock.Anything).Return(map[string]string{}, nil)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := NewCachedConfigService(mocks.configService(), cache.NewMemoryCache(10), time.Minute, logger)
+	warmer := NewWarmer(service, versionLines{
+		{Platform: "android", Major: 14, Minor: 8},
+		{Platform: "android", Major: 14, Minor: 7},
+		{Platform: "ios", Major: 13, Minor: 5},
+	}, 2, logger)
+
+	result, err := warmer.Warmup(context.Background(), []string{"android"})
+	require.NoError(t, err)
+
+	// The line without compatible definitions is skipped, other platforms are not touched
+	assert.Equal(t, WarmupResult{Warmed: 1, Skipped: 1}, result)
+	mocks.platformVersions.AssertNotCalled(t, "GetPlatformVersion", mock.Anything, "ios")
+
+	// Any patch of the warmed line is served from cache
+	_, fresh, ok := service.getCached(context.Background(), resolutionCacheKey(ClientParams{Platform: "android", AppVersion: "14.8.447"}))
+	assert.True(t, ok)
+	assert.True(t, fresh)
+}
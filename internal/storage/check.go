@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// StatementChecker is implemented by repositories with prepared statements
+type StatementChecker interface {
+	// CheckStatements executes the prepared statements without reading data, so that
+	// statements broken by schema changes are detected before clients hit them
+	CheckStatements(ctx context.Context) error
+}
+
+// CheckStatements executes the resource statements with arguments matching no rows
+func (r *ResourceRepositoryImpl) CheckStatements(ctx context.Context) error {
+	var resource Resource
+	if err := ignoreNoRows(r.getResourceStmt.GetContext(ctx, &resource, "", "")); err != nil {
+		return fmt.Errorf("%s getResource statement: %w", r.tableName, err)
+	}
+
+	var err error
+	switch r.compatibility {
+	case MajorOnly:
+		err = r.getCompatibleResourceStmt.GetContext(ctx, &resource, "", -1)
+	case MajorMinor:
+		err = r.getCompatibleResourceStmt.GetContext(ctx, &resource, "", -1, -1)
+	}
+	if err := ignoreNoRows(err); err != nil {
+		return fmt.Errorf("%s getCompatibleResource statement: %w", r.tableName, err)
+	}
+	return nil
+}
+
+// CheckStatements executes the URL statement without reading rows
+func (r *URLRepositoryImpl) CheckStatements(ctx context.Context) error {
+	if err := checkQuery(ctx, r.listURLsStmt); err != nil {
+		return fmt.Errorf("%s listURLs statement: %w", r.tableName, err)
+	}
+	return nil
+}
+
+// CheckStatements executes the entry points statement without reading rows
+func (r *EntryPointRepository) CheckStatements(ctx context.Context) error {
+	if err := checkQuery(ctx, r.query); err != nil {
+		return fmt.Errorf("entry points statement: %w", err)
+	}
+	return nil
+}
+
+// checkQuery executes a statement and closes the rows right away
+func checkQuery(ctx context.Context, stmt *sqlx.Stmt) error {
+	rows, err := stmt.QueryxContext(ctx)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// ignoreNoRows treats an empty result as success
+func ignoreNoRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
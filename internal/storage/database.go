@@ -3,27 +3,81 @@ package storage
 import (
 	"fmt"
 	"log/slog"
+	"net/url"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/jmoiron/sqlx"
 )
 
+// Storage drivers
+const (
+	DriverMySQL    = "mysql"    // MySQL database
+	DriverPostgres = "postgres" // PostgreSQL database
+	DriverFile     = "file"     // JSON or YAML files in a directory
+)
+
+// pgxDriverName is the database/sql driver name registered by pgx
+const pgxDriverName = "pgx"
+
 type Config struct {
+	Driver   string // DriverMySQL or DriverPostgres
 	Host     string
 	Port     string
 	User     string
 	Password string
 	DBName   string
+	SSLMode  string // PostgreSQL only
 }
 
 // New creates a new database connection using sqlx
 func New(config *Config) (*sqlx.DB, error) {
-	// Build MySQL connection string
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&multiStatements=true",
-		config.User, config.Password, config.Host, config.Port, config.DBName)
+	return Open(config.Driver, config.DSN())
+}
+
+// DSN builds connection string for the configured driver
+func (c *Config) DSN() string {
+	if c.Driver == DriverPostgres {
+		dsn := url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(c.User, c.Password),
+			Host:   c.Host + ":" + c.Port,
+			Path:   c.DBName,
+		}
+		if c.SSLMode != "" {
+			dsn.RawQuery = url.Values{"sslmode": {c.SSLMode}}.Encode()
+		}
+		return dsn.String()
+	}
+
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&multiStatements=true",
+		c.User, c.Password, c.Host, c.Port, c.DBName)
+}
+
+// DriverFromDSN detects the driver by connection string,
+// PostgreSQL URLs start with postgres:// and everything else is treated as MySQL DSN
+func DriverFromDSN(dsn string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		return DriverPostgres
+	}
+	return DriverMySQL
+}
+
+// Open creates a new database connection using the driver and connection string
+func Open(driver, dsn string) (*sqlx.DB, error) {
+	var driverName string
+	switch driver {
+	case DriverMySQL:
+		driverName = "mysql"
+	case DriverPostgres:
+		driverName = pgxDriverName
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
 
-	// Open MySQL database with sqlx
-	db, err := sqlx.Connect("mysql", dsn)
+	// Open database with sqlx
+	db, err := sqlx.Connect(driverName, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -33,6 +87,6 @@ func New(config *Config) (*sqlx.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	slog.Info("database connection established")
+	slog.Info("database connection established", "driver", driver)
 	return db, nil
 }
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterDatabaseMetrics reports connection pool statistics of the database
+func RegisterDatabaseMetrics(meter metric.Meter, db *sqlx.DB) (metric.Registration, error) {
+	connections, err := meter.Int64ObservableGauge(
+		"db.pool.connections",
+		metric.WithDescription("Number of pool connections by state: in_use or idle"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	maxOpen, err := meter.Int64ObservableGauge(
+		"db.pool.max_open",
+		metric.WithDescription("Maximum number of open connections, 0 means unlimited"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	waits, err := meter.Int64ObservableCounter(
+		"db.pool.waits",
+		metric.WithDescription("Number of times a query waited for a free connection"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	waitDuration, err := meter.Float64ObservableCounter(
+		"db.pool.wait_duration",
+		metric.WithDescription("Total time spent waiting for a free connection"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	closed, err := meter.Int64ObservableCounter(
+		"db.pool.closed",
+		metric.WithDescription("Number of connections closed by the pool limits"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		stats := db.Stats()
+		observer.ObserveInt64(connections, int64(stats.InUse), metric.WithAttributes(attribute.String("state", "in_use")))
+		observer.ObserveInt64(connections, int64(stats.Idle), metric.WithAttributes(attribute.String("state", "idle")))
+		observer.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections))
+		observer.ObserveInt64(waits, stats.WaitCount)
+		observer.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds())
+		observer.ObserveInt64(closed, stats.MaxIdleClosed+stats.MaxIdleTimeClosed+stats.MaxLifetimeClosed)
+		return nil
+	}, connections, maxOpen, waits, waitDuration, closed)
+}
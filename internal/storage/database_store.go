@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DatabaseStore keeps a snapshot of the dataset stored in the database
+// and refreshes it periodically or on demand
+type DatabaseStore struct {
+	*SnapshotStore
+	repository *DatasetRepository
+	interval   time.Duration
+	logger     *slog.Logger
+	refreshMu  sync.Mutex
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewDatabaseStore loads the dataset from the database and starts refreshing it.
+// Zero interval disables periodic refreshing, the snapshot is then updated only by Refresh.
+func NewDatabaseStore(ctx context.Context, db *sqlx.DB, interval time.Duration, logger *slog.Logger) (*DatabaseStore, error) {
+	repository := NewDatasetRepository(db)
+
+	snapshot, err := loadDatabaseSnapshot(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshCtx, cancel := context.WithCancel(context.Background())
+	store := &DatabaseStore{
+		SnapshotStore: NewSnapshotStore(snapshot),
+		repository:    repository,
+		interval:      interval,
+		logger:        logger,
+		cancel:        cancel,
+	}
+
+	if interval > 0 {
+		store.wg.Add(1)
+		go store.watch(refreshCtx)
+	}
+
+	logger.Info("database snapshot loaded")
+	return store, nil
+}
+
+// Refresh reloads the dataset from the database and replaces the snapshot,
+// changed data is reported to the OnChange function. On error the previous snapshot is kept.
+func (s *DatabaseStore) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	snapshot, err := loadDatabaseSnapshot(ctx, s.repository)
+	if err != nil {
+		return err
+	}
+
+	s.Replace(ctx, snapshot)
+	return nil
+}
+
+// Close stops refreshing the snapshot, the database connection is not closed
+func (s *DatabaseStore) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// watch periodically refreshes the snapshot
+func (s *DatabaseStore) watch(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Error("failed to refresh database snapshot", "error", err.Error())
+			}
+		}
+	}
+}
+
+// loadDatabaseSnapshot reads the dataset from the database and builds a snapshot from it
+func loadDatabaseSnapshot(ctx context.Context, repository *DatasetRepository) (*Snapshot, error) {
+	dataset, err := repository.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := NewSnapshot(dataset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build database snapshot: %w", err)
+	}
+	return snapshot, nil
+}
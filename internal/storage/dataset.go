@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Masterminds/semver"
+)
+
+// Table names of the configuration data
+const (
+	AssetsTable           = "assets"
+	DefinitionsTable      = "definitions"
+	AssetURLsTable        = "asset_urls"
+	DefinitionURLsTable   = "definition_urls"
+	PlatformVersionsTable = "platform_versions"
+	EntryPointsTable      = "entry_points"
+)
+
+var (
+	// versionPattern matches the SemVer format accepted by the API (MAJOR.MINOR.PATCH)
+	versionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+	// hashPattern matches a hex encoded SHA-256 digest
+	hashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// Dataset represents the complete set of configuration data
+// that can be imported into or exported from the storage
+type Dataset struct {
+	Assets         map[string][]Resource // Assets by platform
+	Definitions    map[string][]Resource // Definitions by platform
+	AssetURLs      []string
+	DefinitionURLs []string
+
+	// Optional sections, nil means the section is not managed by the dataset
+	PlatformVersions map[string]PlatformVersion // Platform versions by platform
+	EntryPoints      map[string]string          // Entry point URLs by key
+}
+
+// Validate checks versions, hashes and URLs of the dataset and returns all found problems
+func (d *Dataset) Validate() error {
+	var errs []error
+	errs = append(errs, validateResources(AssetsTable, d.Assets)...)
+	errs = append(errs, validateResources(DefinitionsTable, d.Definitions)...)
+	errs = append(errs, validateURLs(AssetURLsTable, d.AssetURLs)...)
+	errs = append(errs, validateURLs(DefinitionURLsTable, d.DefinitionURLs)...)
+	errs = append(errs, validatePlatformVersions(d.PlatformVersions)...)
+	errs = append(errs, validateEntryPoints(d.EntryPoints)...)
+	return errors.Join(errs...)
+}
+
+func validateResources(kind string, resources map[string][]Resource) []error {
+	var errs []error
+	for platform, list := range resources {
+		if platform == "" {
+			errs = append(errs, fmt.Errorf("%s: empty platform name", kind))
+		}
+
+		seen := make(map[string]bool, len(list))
+		for _, resource := range list {
+			if err := validateVersion(resource.Version); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s: %w", kind, platform, err))
+			}
+			if !hashPattern.MatchString(resource.Hash) {
+				errs = append(errs, fmt.Errorf("%s: %s %s: invalid hash %q", kind, platform, resource.Version, resource.Hash))
+			}
+			if seen[resource.Version] {
+				errs = append(errs, fmt.Errorf("%s: %s: duplicate version %s", kind, platform, resource.Version))
+			}
+			seen[resource.Version] = true
+		}
+	}
+	return errs
+}
+
+func validateURLs(kind string, urls []string) []error {
+	var errs []error
+	seen := make(map[string]bool, len(urls))
+	for _, url := range urls {
+		if url == "" {
+			errs = append(errs, fmt.Errorf("%s: empty url", kind))
+			continue
+		}
+		if seen[url] {
+			errs = append(errs, fmt.Errorf("%s: duplicate url %s", kind, url))
+		}
+		seen[url] = true
+	}
+	return errs
+}
+
+func validatePlatformVersions(platformVersions map[string]PlatformVersion) []error {
+	var errs []error
+	for platform, platformVersion := range platformVersions {
+		if platform == "" {
+			errs = append(errs, fmt.Errorf("%s: empty platform name", PlatformVersionsTable))
+		}
+		if err := validateVersion(platformVersion.RequiredVersion); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: required: %w", PlatformVersionsTable, platform, err))
+		}
+		if err := validateVersion(platformVersion.StoreVersion); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: store: %w", PlatformVersionsTable, platform, err))
+		}
+	}
+	return errs
+}
+
+func validateEntryPoints(entryPoints map[string]string) []error {
+	var errs []error
+	for key, url := range entryPoints {
+		if key == "" {
+			errs = append(errs, fmt.Errorf("%s: empty key", EntryPointsTable))
+		}
+		if url == "" {
+			errs = append(errs, fmt.Errorf("%s: %s: empty url", EntryPointsTable, key))
+		}
+	}
+	return errs
+}
+
+// validateVersion checks that version is a valid MAJOR.MINOR.PATCH string
+func validateVersion(version string) error {
+	if !versionPattern.MatchString(version) {
+		return fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", version)
+	}
+	if _, err := semver.NewVersion(version); err != nil {
+		return fmt.Errorf("invalid version %q: %w", version, err)
+	}
+	return nil
+}
+
+// versionComponents splits version into major, minor and patch components
+func versionComponents(version string) (major, minor, patch int64, err error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to parse version %s: %w", version, err)
+	}
+	return v.Major(), v.Minor(), v.Patch(), nil
+}
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"sort"
+)
+
+// DatasetDiff describes changes required to turn one dataset into another
+type DatasetDiff struct {
+	Assets         ResourceDiff `json:"assets"`
+	Definitions    ResourceDiff `json:"definitions"`
+	AssetURLs      URLDiff      `json:"asset_urls"`
+	DefinitionURLs URLDiff      `json:"definition_urls"`
+
+	PlatformVersions PlatformVersionDiff `json:"platform_versions"`
+	EntryPoints      EntryPointDiff      `json:"entry_points"`
+}
+
+// ResourceDiff describes changes of versioned resources (assets, definitions, etc.)
+type ResourceDiff struct {
+	Added   []PlatformResource `json:"added,omitempty"`
+	Changed []ResourceChange   `json:"changed,omitempty"`
+	Removed []PlatformResource `json:"removed,omitempty"`
+}
+
+// PlatformResource is a resource bound to a platform
+type PlatformResource struct {
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+	Hash     string `json:"hash"`
+}
+
+// ResourceChange describes a resource whose hash has changed
+type ResourceChange struct {
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+	OldHash  string `json:"old_hash"`
+	NewHash  string `json:"new_hash"`
+}
+
+// URLDiff describes changes of a URL list
+type URLDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// PlatformVersionDiff describes changes of platform versions
+type PlatformVersionDiff struct {
+	Added   []PlatformVersionValue  `json:"added,omitempty"`
+	Changed []PlatformVersionChange `json:"changed,omitempty"`
+	Removed []PlatformVersionValue  `json:"removed,omitempty"`
+}
+
+// PlatformVersionValue is a platform version bound to a platform
+type PlatformVersionValue struct {
+	Platform string `json:"platform"`
+	PlatformVersion
+}
+
+// PlatformVersionChange describes a platform whose versions have changed
+type PlatformVersionChange struct {
+	Platform string          `json:"platform"`
+	Old      PlatformVersion `json:"old"`
+	New      PlatformVersion `json:"new"`
+}
+
+// EntryPointDiff describes changes of entry points
+type EntryPointDiff struct {
+	Added   []EntryPointValue  `json:"added,omitempty"`
+	Changed []EntryPointChange `json:"changed,omitempty"`
+	Removed []EntryPointValue  `json:"removed,omitempty"`
+}
+
+// EntryPointValue is an entry point URL bound to its key
+type EntryPointValue struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// EntryPointChange describes an entry point whose URL has changed
+type EntryPointChange struct {
+	Key    string `json:"key"`
+	OldURL string `json:"old_url"`
+	NewURL string `json:"new_url"`
+}
+
+// DiffDatasets calculates changes required to turn current dataset into desired one.
+// Optional sections that are absent in the desired dataset are left unchanged.
+func DiffDatasets(current, desired *Dataset) *DatasetDiff {
+	diff := &DatasetDiff{
+		Assets:         diffResources(current.Assets, desired.Assets),
+		Definitions:    diffResources(current.Definitions, desired.Definitions),
+		AssetURLs:      diffURLs(current.AssetURLs, desired.AssetURLs),
+		DefinitionURLs: diffURLs(current.DefinitionURLs, desired.DefinitionURLs),
+	}
+
+	if desired.PlatformVersions != nil {
+		diff.PlatformVersions = diffPlatformVersions(current.PlatformVersions, desired.PlatformVersions)
+	}
+
+	if desired.EntryPoints != nil {
+		diff.EntryPoints = diffEntryPoints(current.EntryPoints, desired.EntryPoints)
+	}
+
+	return diff
+}
+
+// IsEmpty reports whether the diff contains no changes
+func (d *DatasetDiff) IsEmpty() bool {
+	return d.Assets.IsEmpty() &&
+		d.Definitions.IsEmpty() &&
+		d.AssetURLs.IsEmpty() &&
+		d.DefinitionURLs.IsEmpty() &&
+		d.PlatformVersions.IsEmpty() &&
+		d.EntryPoints.IsEmpty()
+}
+
+// WithoutRemovals returns a copy of the diff that only adds and updates data
+func (d *DatasetDiff) WithoutRemovals() *DatasetDiff {
+	result := *d
+	result.Assets.Removed = nil
+	result.Definitions.Removed = nil
+	result.AssetURLs.Removed = nil
+	result.DefinitionURLs.Removed = nil
+	result.PlatformVersions.Removed = nil
+	result.EntryPoints.Removed = nil
+	return &result
+}
+
+// IsEmpty reports whether the diff contains no changes
+func (d ResourceDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// IsEmpty reports whether the diff contains no changes
+func (d URLDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// IsEmpty reports whether the diff contains no changes
+func (d PlatformVersionDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// IsEmpty reports whether the diff contains no changes
+func (d EntryPointDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+func diffResources(current, desired map[string][]Resource) ResourceDiff {
+	var diff ResourceDiff
+
+	currentIndex := indexResources(current)
+	desiredIndex := indexResources(desired)
+
+	for key, resource := range desiredIndex {
+		existing, ok := currentIndex[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, resource)
+		case existing.Hash != resource.Hash:
+			diff.Changed = append(diff.Changed, ResourceChange{
+				Platform: resource.Platform,
+				Version:  resource.Version,
+				OldHash:  existing.Hash,
+				NewHash:  resource.Hash,
+			})
+		}
+	}
+
+	for key, resource := range currentIndex {
+		if _, ok := desiredIndex[key]; !ok {
+			diff.Removed = append(diff.Removed, resource)
+		}
+	}
+
+	sortPlatformResources(diff.Added)
+	sortPlatformResources(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		if diff.Changed[i].Platform != diff.Changed[j].Platform {
+			return diff.Changed[i].Platform < diff.Changed[j].Platform
+		}
+		return diff.Changed[i].Version < diff.Changed[j].Version
+	})
+
+	return diff
+}
+
+// indexResources flattens resources into a map keyed by platform and version
+func indexResources(resources map[string][]Resource) map[[2]string]PlatformResource {
+	index := make(map[[2]string]PlatformResource)
+	for platform, list := range resources {
+		for _, resource := range list {
+			index[[2]string{platform, resource.Version}] = PlatformResource{
+				Platform: platform,
+				Version:  resource.Version,
+				Hash:     resource.Hash,
+			}
+		}
+	}
+	return index
+}
+
+func sortPlatformResources(resources []PlatformResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Platform != resources[j].Platform {
+			return resources[i].Platform < resources[j].Platform
+		}
+		return resources[i].Version < resources[j].Version
+	})
+}
+
+func diffURLs(current, desired []string) URLDiff {
+	var diff URLDiff
+
+	currentSet := make(map[string]bool, len(current))
+	for _, url := range current {
+		currentSet[url] = true
+	}
+
+	desiredSet := make(map[string]bool, len(desired))
+	for _, url := range desired {
+		desiredSet[url] = true
+		if !currentSet[url] {
+			diff.Added = append(diff.Added, url)
+		}
+	}
+
+	for _, url := range current {
+		if !desiredSet[url] {
+			diff.Removed = append(diff.Removed, url)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+func diffPlatformVersions(current, desired map[string]PlatformVersion) PlatformVersionDiff {
+	var diff PlatformVersionDiff
+
+	for _, platform := range sortedKeys(desired) {
+		platformVersion := desired[platform]
+		existing, ok := current[platform]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, PlatformVersionValue{Platform: platform, PlatformVersion: platformVersion})
+		case existing != platformVersion:
+			diff.Changed = append(diff.Changed, PlatformVersionChange{Platform: platform, Old: existing, New: platformVersion})
+		}
+	}
+
+	for _, platform := range sortedKeys(current) {
+		if _, ok := desired[platform]; !ok {
+			diff.Removed = append(diff.Removed, PlatformVersionValue{Platform: platform, PlatformVersion: current[platform]})
+		}
+	}
+
+	return diff
+}
+
+func diffEntryPoints(current, desired map[string]string) EntryPointDiff {
+	var diff EntryPointDiff
+
+	for _, key := range sortedKeys(desired) {
+		url := desired[key]
+		existing, ok := current[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, EntryPointValue{Key: key, URL: url})
+		case existing != url:
+			diff.Changed = append(diff.Changed, EntryPointChange{Key: key, OldURL: existing, NewURL: url})
+		}
+	}
+
+	for _, key := range sortedKeys(current) {
+		if _, ok := desired[key]; !ok {
+			diff.Removed = append(diff.Removed, EntryPointValue{Key: key, URL: current[key]})
+		}
+	}
+
+	return diff
+}
+
+// sortedKeys returns map keys in ascending order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
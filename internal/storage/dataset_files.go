@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dataset file names, the same files are used by the initial data migration.
+// Each file can also be provided in YAML format with .yaml or .yml extension.
+const (
+	AssetsFile         = "assets.json"
+	DefinitionsFile    = "definitions.json"
+	AssetURLsFile      = "assets_urls.json"
+	DefinitionURLsFile = "definitions_urls.json"
+
+	// Optional files
+	PlatformVersionsFile = "platform_versions.json"
+	EntryPointsFile      = "entry_points.json"
+)
+
+// JSON keys of the URL lists inside URL files
+const (
+	assetURLsKey      = "assets_urls"
+	definitionURLsKey = "definitions_urls"
+)
+
+// yamlExtensions are accepted instead of .json extension of dataset files
+var yamlExtensions = []string{".yaml", ".yml"}
+
+// ReadDatasetDir reads a dataset from JSON or YAML files in the directory
+func ReadDatasetDir(dir string) (*Dataset, error) {
+	var dataset Dataset
+
+	if err := readDataFile(dir, AssetsFile, &dataset.Assets); err != nil {
+		return nil, err
+	}
+
+	if err := readDataFile(dir, DefinitionsFile, &dataset.Definitions); err != nil {
+		return nil, err
+	}
+
+	assetURLs := make(map[string][]string)
+	if err := readDataFile(dir, AssetURLsFile, &assetURLs); err != nil {
+		return nil, err
+	}
+	dataset.AssetURLs = assetURLs[assetURLsKey]
+
+	definitionURLs := make(map[string][]string)
+	if err := readDataFile(dir, DefinitionURLsFile, &definitionURLs); err != nil {
+		return nil, err
+	}
+	dataset.DefinitionURLs = definitionURLs[definitionURLsKey]
+
+	if err := readOptionalDataFile(dir, PlatformVersionsFile, &dataset.PlatformVersions); err != nil {
+		return nil, err
+	}
+
+	if err := readOptionalDataFile(dir, EntryPointsFile, &dataset.EntryPoints); err != nil {
+		return nil, err
+	}
+
+	return &dataset, nil
+}
+
+// WriteDatasetDir writes a dataset as JSON files into the directory
+func WriteDatasetDir(dir string, dataset *Dataset) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	files := map[string]any{
+		AssetsFile:         nonNilResources(dataset.Assets),
+		DefinitionsFile:    nonNilResources(dataset.Definitions),
+		AssetURLsFile:      map[string][]string{assetURLsKey: nonNilURLs(dataset.AssetURLs)},
+		DefinitionURLsFile: map[string][]string{definitionURLsKey: nonNilURLs(dataset.DefinitionURLs)},
+	}
+	if dataset.PlatformVersions != nil {
+		files[PlatformVersionsFile] = dataset.PlatformVersions
+	}
+	if dataset.EntryPoints != nil {
+		files[EntryPointsFile] = dataset.EntryPoints
+	}
+
+	for name, value := range files {
+		if err := writeJSONFile(filepath.Join(dir, name), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DatasetFiles returns paths of dataset files that exist in the directory
+func DatasetFiles(dir string) []string {
+	var paths []string
+	for _, name := range []string{AssetsFile, DefinitionsFile, AssetURLsFile, DefinitionURLsFile, PlatformVersionsFile, EntryPointsFile} {
+		if path, err := findDataFile(dir, name); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// findDataFile returns path of the dataset file in JSON or YAML format
+func findDataFile(dir, name string) (string, error) {
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return path, err
+	}
+
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	for _, ext := range yamlExtensions {
+		candidate := filepath.Join(dir, base+ext)
+		if _, err := os.Stat(candidate); !errors.Is(err, fs.ErrNotExist) {
+			return candidate, err
+		}
+	}
+
+	return "", fmt.Errorf("failed to read %s: %w", path, fs.ErrNotExist)
+}
+
+// readDataFile reads the dataset file with the given name from the directory
+func readDataFile(dir, name string, value any) error {
+	path, err := findDataFile(dir, name)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// YAML documents are converted to JSON to reuse JSON field tags
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		var document any
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if data, err = json.Marshal(document); err != nil {
+			return fmt.Errorf("failed to convert %s: %w", path, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// readOptionalDataFile reads the dataset file and leaves value untouched if the file does not exist
+func readOptionalDataFile(dir, name string, value any) error {
+	err := readDataFile(dir, name, value)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func writeJSONFile(path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// nonNilResources makes empty resources to be encoded as {} instead of null
+func nonNilResources(resources map[string][]Resource) map[string][]Resource {
+	if resources == nil {
+		return map[string][]Resource{}
+	}
+	return resources
+}
+
+// nonNilURLs makes empty URL lists to be encoded as [] instead of null
+func nonNilURLs(urls []string) []string {
+	if urls == nil {
+		return []string{}
+	}
+	return urls
+}
@@ -0,0 +1,276 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DatasetRepository loads and updates the complete configuration dataset
+type DatasetRepository struct {
+	db      *sqlx.DB
+	dialect dialect
+}
+
+// NewDatasetRepository creates a new dataset repository
+func NewDatasetRepository(db *sqlx.DB) *DatasetRepository {
+	return &DatasetRepository{
+		db:      db,
+		dialect: dialectOf(db),
+	}
+}
+
+// Load reads the complete dataset from the database. Tables are read in a single read-only
+// transaction, so that a dataset loaded during an import does not mix old and new tables.
+func (r *DatasetRepository) Load(ctx context.Context) (*Dataset, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	assets, err := r.loadResources(ctx, tx, AssetsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, err := r.loadResources(ctx, tx, DefinitionsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	assetURLs, err := r.loadURLs(ctx, tx, AssetURLsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionURLs, err := r.loadURLs(ctx, tx, DefinitionURLsTable)
+	if err != nil {
+		return nil, err
+	}
+
+	platformVersions, err := r.loadPlatformVersions(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	entryPoints, err := r.loadEntryPoints(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &Dataset{
+		Assets:           assets,
+		Definitions:      definitions,
+		AssetURLs:        assetURLs,
+		DefinitionURLs:   definitionURLs,
+		PlatformVersions: platformVersions,
+		EntryPoints:      entryPoints,
+	}, nil
+}
+
+func (r *DatasetRepository) loadResources(ctx context.Context, tx *sqlx.Tx, tableName string) (map[string][]Resource, error) {
+	var rows []struct {
+		Platform string `db:"platform"`
+		Resource
+	}
+	err := tx.SelectContext(ctx, &rows,
+		fmt.Sprintf("SELECT platform, version, hash FROM %s ORDER BY platform, id", tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", tableName, err)
+	}
+
+	resources := make(map[string][]Resource)
+	for _, row := range rows {
+		resources[row.Platform] = append(resources[row.Platform], row.Resource)
+	}
+	return resources, nil
+}
+
+func (r *DatasetRepository) loadURLs(ctx context.Context, tx *sqlx.Tx, tableName string) ([]string, error) {
+	var urls []string
+	err := tx.SelectContext(ctx, &urls, fmt.Sprintf("SELECT url FROM %s ORDER BY id", tableName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", tableName, err)
+	}
+	return urls, nil
+}
+
+func (r *DatasetRepository) loadPlatformVersions(ctx context.Context, tx *sqlx.Tx) (map[string]PlatformVersion, error) {
+	var rows []struct {
+		Platform string `db:"platform"`
+		PlatformVersion
+	}
+	err := tx.SelectContext(ctx, &rows,
+		"SELECT platform, required_version, store_version FROM platform_versions ORDER BY platform")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", PlatformVersionsTable, err)
+	}
+
+	platformVersions := make(map[string]PlatformVersion, len(rows))
+	for _, row := range rows {
+		platformVersions[row.Platform] = row.PlatformVersion
+	}
+	return platformVersions, nil
+}
+
+func (r *DatasetRepository) loadEntryPoints(ctx context.Context, tx *sqlx.Tx) (map[string]string, error) {
+	var rows []EntryPoint
+	err := tx.SelectContext(ctx, &rows,
+		fmt.Sprintf("SELECT id, %s, url FROM entry_points ORDER BY id", r.dialect.quote("key")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", EntryPointsTable, err)
+	}
+
+	entryPoints := make(map[string]string, len(rows))
+	for _, row := range rows {
+		entryPoints[row.Key] = row.URL
+	}
+	return entryPoints, nil
+}
+
+// Apply applies the diff to the database in a single transaction
+func (r *DatasetRepository) Apply(ctx context.Context, diff *DatasetDiff) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // nolint:errcheck
+
+	if err := r.applyResourceDiff(ctx, tx, AssetsTable, diff.Assets); err != nil {
+		return err
+	}
+	if err := r.applyResourceDiff(ctx, tx, DefinitionsTable, diff.Definitions); err != nil {
+		return err
+	}
+	if err := r.applyURLDiff(ctx, tx, AssetURLsTable, diff.AssetURLs); err != nil {
+		return err
+	}
+	if err := r.applyURLDiff(ctx, tx, DefinitionURLsTable, diff.DefinitionURLs); err != nil {
+		return err
+	}
+	if err := r.applyPlatformVersionDiff(ctx, tx, diff.PlatformVersions); err != nil {
+		return err
+	}
+	if err := r.applyEntryPointDiff(ctx, tx, diff.EntryPoints); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *DatasetRepository) applyResourceDiff(ctx context.Context, tx *sqlx.Tx, tableName string, diff ResourceDiff) error {
+	upsert := r.dialect.upsert(tableName,
+		[]string{"platform", "version", "major", "minor", "patch", "hash"},
+		[]string{"platform", "version"},
+		[]string{"hash"})
+
+	upserts := make([]PlatformResource, 0, len(diff.Added)+len(diff.Changed))
+	upserts = append(upserts, diff.Added...)
+	for _, change := range diff.Changed {
+		upserts = append(upserts, PlatformResource{
+			Platform: change.Platform,
+			Version:  change.Version,
+			Hash:     change.NewHash,
+		})
+	}
+
+	for _, resource := range upserts {
+		major, minor, patch, err := versionComponents(resource.Version)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, upsert,
+			resource.Platform, resource.Version, major, minor, patch, resource.Hash); err != nil {
+			return fmt.Errorf("failed to upsert %s %s %s: %w", tableName, resource.Platform, resource.Version, err)
+		}
+	}
+
+	remove := tx.Rebind(fmt.Sprintf("DELETE FROM %s WHERE platform = ? AND version = ?", tableName))
+	for _, resource := range diff.Removed {
+		if _, err := tx.ExecContext(ctx, remove, resource.Platform, resource.Version); err != nil {
+			return fmt.Errorf("failed to delete %s %s %s: %w", tableName, resource.Platform, resource.Version, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *DatasetRepository) applyURLDiff(ctx context.Context, tx *sqlx.Tx, tableName string, diff URLDiff) error {
+	insert := r.dialect.upsert(tableName, []string{"url"}, []string{"url"}, nil)
+	for _, url := range diff.Added {
+		if _, err := tx.ExecContext(ctx, insert, url); err != nil {
+			return fmt.Errorf("failed to insert %s %s: %w", tableName, url, err)
+		}
+	}
+
+	remove := tx.Rebind(fmt.Sprintf("DELETE FROM %s WHERE url = ?", tableName))
+	for _, url := range diff.Removed {
+		if _, err := tx.ExecContext(ctx, remove, url); err != nil {
+			return fmt.Errorf("failed to delete %s %s: %w", tableName, url, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *DatasetRepository) applyPlatformVersionDiff(ctx context.Context, tx *sqlx.Tx, diff PlatformVersionDiff) error {
+	upsert := r.dialect.upsert(PlatformVersionsTable,
+		[]string{"platform", "required_version", "store_version"},
+		[]string{"platform"},
+		[]string{"required_version", "store_version"})
+
+	upserts := make([]PlatformVersionValue, 0, len(diff.Added)+len(diff.Changed))
+	upserts = append(upserts, diff.Added...)
+	for _, change := range diff.Changed {
+		upserts = append(upserts, PlatformVersionValue{Platform: change.Platform, PlatformVersion: change.New})
+	}
+
+	for _, value := range upserts {
+		if _, err := tx.ExecContext(ctx, upsert, value.Platform, value.RequiredVersion, value.StoreVersion); err != nil {
+			return fmt.Errorf("failed to upsert %s %s: %w", PlatformVersionsTable, value.Platform, err)
+		}
+	}
+
+	for _, value := range diff.Removed {
+		if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM platform_versions WHERE platform = ?"), value.Platform); err != nil {
+			return fmt.Errorf("failed to delete %s %s: %w", PlatformVersionsTable, value.Platform, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *DatasetRepository) applyEntryPointDiff(ctx context.Context, tx *sqlx.Tx, diff EntryPointDiff) error {
+	upsert := r.dialect.upsert(EntryPointsTable, []string{"key", "url"}, []string{"key"}, []string{"url"})
+	remove := tx.Rebind(fmt.Sprintf("DELETE FROM entry_points WHERE %s = ?", r.dialect.quote("key")))
+
+	upserts := make([]EntryPointValue, 0, len(diff.Added)+len(diff.Changed))
+	upserts = append(upserts, diff.Added...)
+	for _, change := range diff.Changed {
+		upserts = append(upserts, EntryPointValue{Key: change.Key, URL: change.NewURL})
+	}
+
+	for _, value := range upserts {
+		if _, err := tx.ExecContext(ctx, upsert, value.Key, value.URL); err != nil {
+			return fmt.Errorf("failed to upsert %s %s: %w", EntryPointsTable, value.Key, err)
+		}
+	}
+
+	for _, value := range diff.Removed {
+		if _, err := tx.ExecContext(ctx, remove, value.Key); err != nil {
+			return fmt.Errorf("failed to delete %s %s: %w", EntryPointsTable, value.Key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testHashA = "828e6360af99ad85332c23c613a772d7392b9d0fadb70529d808d71e3f9b3a2f"
+	testHashB = "7a2d9abb574c90271470b4f2068847cc341a86712cd142b16c42cc585caec280"
+)
+
+func TestReadDatasetDir_MigrationData(t *testing.T) {
+	dataset, err := ReadDatasetDir("../../deployments/db/migrations/data")
+	require.NoError(t, err)
+
+	assert.NoError(t, dataset.Validate())
+	assert.Len(t, dataset.Assets["android"], 10)
+	assert.Len(t, dataset.Definitions["ios"], 10)
+	assert.Contains(t, dataset.AssetURLs, "dhm.cdn.application.com")
+	assert.Contains(t, dataset.DefinitionURLs, "fmp.cdn.application.com")
+}
+
+func TestWriteDatasetDir_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	dataset := &Dataset{
+		Assets:         map[string][]Resource{"android": {{Version: "14.8.447", Hash: testHashA}}},
+		Definitions:    map[string][]Resource{"ios": {{Version: "14.5.580", Hash: testHashB}}},
+		AssetURLs:      []string{"a.cdn.application.com"},
+		DefinitionURLs: []string{"d.cdn.application.com"},
+	}
+
+	require.NoError(t, WriteDatasetDir(dir, dataset))
+
+	loaded, err := ReadDatasetDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, dataset, loaded)
+}
+
+func TestDataset_Validate(t *testing.T) {
+	dataset := &Dataset{
+		Assets: map[string][]Resource{
+			"android": {
+				{Version: "14.8.447", Hash: testHashA},
+				{Version: "v14.8.448", Hash: testHashA},
+				{Version: "14.8.447", Hash: "not-a-hash"},
+			},
+		},
+		AssetURLs: []string{"a.cdn.application.com", "a.cdn.application.com", ""},
+	}
+
+	err := dataset.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid version "v14.8.448"`)
+	assert.Contains(t, err.Error(), `invalid hash "not-a-hash"`)
+	assert.Contains(t, err.Error(), "duplicate version 14.8.447")
+	assert.Contains(t, err.Error(), "duplicate url a.cdn.application.com")
+	assert.Contains(t, err.Error(), "empty url")
+}
+
+func TestDiffDatasets(t *testing.T) {
+	current := &Dataset{
+		Assets: map[string][]Resource{
+			"android": {
+				{Version: "14.8.447", Hash: testHashA},
+				{Version: "13.2.528", Hash: testHashA},
+			},
+		},
+		AssetURLs: []string{"a.cdn.application.com", "old.cdn.application.com"},
+	}
+	desired := &Dataset{
+		Assets: map[string][]Resource{
+			"android": {
+				{Version: "14.8.447", Hash: testHashB},
+				{Version: "14.9.1", Hash: testHashA},
+			},
+		},
+		AssetURLs: []string{"a.cdn.application.com", "new.cdn.application.com"},
+	}
+
+	diff := DiffDatasets(current, desired)
+
+	assert.Equal(t, []PlatformResource{{Platform: "android", Version: "14.9.1", Hash: testHashA}}, diff.Assets.Added)
+	assert.Equal(t, []ResourceChange{{Platform: "android", Version: "14.8.447", OldHash: testHashA, NewHash: testHashB}}, diff.Assets.Changed)
+	assert.Equal(t, []PlatformResource{{Platform: "android", Version: "13.2.528", Hash: testHashA}}, diff.Assets.Removed)
+	assert.Equal(t, []string{"new.cdn.application.com"}, diff.AssetURLs.Added)
+	assert.Equal(t, []string{"old.cdn.application.com"}, diff.AssetURLs.Removed)
+	assert.True(t, diff.Definitions.IsEmpty())
+	assert.False(t, diff.IsEmpty())
+
+	withoutRemovals := diff.WithoutRemovals()
+	assert.Empty(t, withoutRemovals.Assets.Removed)
+	assert.Empty(t, withoutRemovals.AssetURLs.Removed)
+	assert.Len(t, diff.Assets.Removed, 1, "original diff must not be modified")
+
+	assert.True(t, DiffDatasets(desired, desired).IsEmpty())
+}
+
+func TestDiffDatasets_OptionalSections(t *testing.T) {
+	current := &Dataset{
+		PlatformVersions: map[string]PlatformVersion{
+			"android": {RequiredVersion: "12.2.423", StoreVersion: "13.7.556"},
+			"ios":     {RequiredVersion: "12.2.423", StoreVersion: "13.7.556"},
+		},
+		EntryPoints: map[string]string{
+			BackendEntryPointKey: "api.application.com/jsonrpc/v2",
+			NotificationsKey:     "notifications.application.com/jsonrpc/v1",
+		},
+	}
+
+	// Sections absent in the desired dataset are not managed and produce no changes
+	assert.True(t, DiffDatasets(current, &Dataset{}).IsEmpty())
+
+	desired := &Dataset{
+		PlatformVersions: map[string]PlatformVersion{
+			"android": {RequiredVersion: "12.2.423", StoreVersion: "14.8.447"},
+		},
+		EntryPoints: map[string]string{
+			BackendEntryPointKey: "api.application.com/jsonrpc/v3",
+			NotificationsKey:     "notifications.application.com/jsonrpc/v1",
+		},
+	}
+
+	diff := DiffDatasets(current, desired)
+
+	assert.Equal(t, []PlatformVersionChange{{
+		Platform: "android",
+		Old:      PlatformVersion{RequiredVersion: "12.2.423", StoreVersion: "13.7.556"},
+		New:      PlatformVersion{RequiredVersion: "12.2.423", StoreVersion: "14.8.447"},
+	}}, diff.PlatformVersions.Changed)
+	assert.Equal(t, []PlatformVersionValue{{
+		Platform:        "ios",
+		PlatformVersion: PlatformVersion{RequiredVersion: "12.2.423", StoreVersion: "13.7.556"},
+	}}, diff.PlatformVersions.Removed)
+	assert.Equal(t, []EntryPointChange{{
+		Key:    BackendEntryPointKey,
+		OldURL: "api.application.com/jsonrpc/v2",
+		NewURL: "api.application.com/jsonrpc/v3",
+	}}, diff.EntryPoints.Changed)
+	assert.Empty(t, diff.EntryPoints.Added)
+	assert.Empty(t, diff.EntryPoints.Removed)
+}
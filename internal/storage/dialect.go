@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// dialect hides SQL differences between supported databases.
+// Queries are written with ? placeholders and rebound for the driver.
+type dialect struct {
+	driver string
+}
+
+// dialectOf returns the dialect of the database connection
+func dialectOf(db *sqlx.DB) dialect {
+	return dialect{driver: db.DriverName()}
+}
+
+// isPostgres reports whether the dialect is PostgreSQL
+func (d dialect) isPostgres() bool {
+	return d.driver == pgxDriverName
+}
+
+// rebind replaces ? placeholders with the driver specific ones
+func (d dialect) rebind(query string) string {
+	return sqlx.Rebind(sqlx.BindType(d.driver), query)
+}
+
+// quote quotes an identifier that is a reserved word in some databases (e.g. key)
+func (d dialect) quote(identifier string) string {
+	if d.isPostgres() {
+		return `"` + identifier + `"`
+	}
+	return "`" + identifier + "`"
+}
+
+// upsert builds a query inserting a row or updating updateColumns when a row
+// with the same conflictColumns exists. Without updateColumns existing rows are kept.
+func (d dialect) upsert(tableName string, columns, conflictColumns, updateColumns []string) string {
+	quoted := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = d.quote(column)
+		placeholders[i] = "?"
+	}
+
+	var builder strings.Builder
+	if !d.isPostgres() && len(updateColumns) == 0 {
+		builder.WriteString("INSERT IGNORE INTO ")
+	} else {
+		builder.WriteString("INSERT INTO ")
+	}
+	fmt.Fprintf(&builder, "%s (%s) VALUES (%s)", tableName, strings.Join(quoted, ", "), strings.Join(placeholders, ", "))
+
+	if d.isPostgres() {
+		conflict := make([]string, len(conflictColumns))
+		for i, column := range conflictColumns {
+			conflict[i] = d.quote(column)
+		}
+		fmt.Fprintf(&builder, " ON CONFLICT (%s)", strings.Join(conflict, ", "))
+
+		if len(updateColumns) == 0 {
+			builder.WriteString(" DO NOTHING")
+		} else {
+			updates := make([]string, len(updateColumns))
+			for i, column := range updateColumns {
+				updates[i] = fmt.Sprintf("%s = EXCLUDED.%s", d.quote(column), d.quote(column))
+			}
+			fmt.Fprintf(&builder, " DO UPDATE SET %s", strings.Join(updates, ", "))
+		}
+	} else if len(updateColumns) > 0 {
+		updates := make([]string, len(updateColumns))
+		for i, column := range updateColumns {
+			updates[i] = fmt.Sprintf("%s = VALUES(%s)", d.quote(column), d.quote(column))
+		}
+		fmt.Fprintf(&builder, " ON DUPLICATE KEY UPDATE %s", strings.Join(updates, ", "))
+	}
+
+	return d.rebind(builder.String())
+}
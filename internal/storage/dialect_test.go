@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialect_Upsert(t *testing.T) {
+	mysql := dialect{driver: DriverMySQL}
+	postgres := dialect{driver: pgxDriverName}
+
+	assert.Equal(t,
+		"INSERT INTO entry_points (`key`, `url`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `url` = VALUES(`url`)",
+		mysql.upsert(EntryPointsTable, []string{"key", "url"}, []string{"key"}, []string{"url"}))
+	assert.Equal(t,
+		`INSERT INTO entry_points ("key", "url") VALUES ($1, $2) ON CONFLICT ("key") DO UPDATE SET "url" = EXCLUDED."url"`,
+		postgres.upsert(EntryPointsTable, []string{"key", "url"}, []string{"key"}, []string{"url"}))
+
+	assert.Equal(t,
+		"INSERT IGNORE INTO asset_urls (`url`) VALUES (?)",
+		mysql.upsert(AssetURLsTable, []string{"url"}, []string{"url"}, nil))
+	assert.Equal(t,
+		`INSERT INTO asset_urls ("url") VALUES ($1) ON CONFLICT ("url") DO NOTHING`,
+		postgres.upsert(AssetURLsTable, []string{"url"}, []string{"url"}, nil))
+}
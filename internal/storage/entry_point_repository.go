@@ -15,7 +15,7 @@ type EntryPointRepository struct {
 
 // NewEntryPointRepository creates a new entry point repository
 func NewEntryPointRepository(db *sqlx.DB) (*EntryPointRepository, error) {
-	query := "SELECT `key`, url FROM entry_points"
+	query := fmt.Sprintf("SELECT %s, url FROM entry_points", dialectOf(db).quote("key"))
 	stmt, err := db.PreparexContext(context.Background(), query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare entry points query: %w", err)
@@ -28,7 +28,10 @@ func NewEntryPointRepository(db *sqlx.DB) (*EntryPointRepository, error) {
 }
 
 // Get retrieves all entry points as a map of key to URL
-func (r *EntryPointRepository) Get(ctx context.Context) (map[string]string, error) {
+func (r *EntryPointRepository) Get(ctx context.Context) (_ map[string]string, err error) {
+	ctx, q := startQuery(ctx, "EntryPointRepository.Get", "entry_points")
+	defer func() { q.end(err) }()
+
 	rows, err := r.query.QueryxContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query entry points: %w", err)
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileStore keeps a snapshot of the dataset stored in a directory
+// and reloads it when the files change
+type FileStore struct {
+	*SnapshotStore
+	dir         string
+	interval    time.Duration
+	logger      *slog.Logger
+	fingerprint string
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// NewFileStore loads the dataset from the directory and starts watching it for changes.
+// Files are checked for changes every interval, zero interval disables reloading.
+func NewFileStore(dir string, interval time.Duration, logger *slog.Logger) (*FileStore, error) {
+	fingerprint, err := datasetFingerprint(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := loadFileSnapshot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store := &FileStore{
+		SnapshotStore: NewSnapshotStore(snapshot),
+		dir:           dir,
+		interval:      interval,
+		logger:        logger,
+		fingerprint:   fingerprint,
+		cancel:        cancel,
+	}
+
+	if interval > 0 {
+		store.wg.Add(1)
+		go store.watch(ctx)
+	}
+
+	logger.Info("file storage loaded", "dir", dir)
+	return store, nil
+}
+
+// Close stops watching the directory
+func (s *FileStore) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// watch periodically checks the files and reloads the snapshot when they change
+func (s *FileStore) watch(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reload(ctx)
+		}
+	}
+}
+
+// reload rebuilds the snapshot if the files have changed since the last load.
+// Invalid data is logged and the previous snapshot is kept.
+func (s *FileStore) reload(ctx context.Context) {
+	fingerprint, err := datasetFingerprint(s.dir)
+	if err != nil {
+		s.logger.Error("failed to check storage files", "dir", s.dir, "error", err.Error())
+		return
+	}
+	if fingerprint == s.fingerprint {
+		return
+	}
+
+	snapshot, err := loadFileSnapshot(s.dir)
+	if err != nil {
+		s.logger.Error("failed to reload storage files", "dir", s.dir, "error", err.Error())
+		return
+	}
+
+	s.Replace(ctx, snapshot)
+	s.fingerprint = fingerprint
+	s.logger.Info("file storage reloaded", "dir", s.dir)
+}
+
+// loadFileSnapshot reads and validates the dataset and builds a snapshot from it
+func loadFileSnapshot(dir string) (*Snapshot, error) {
+	dataset, err := ReadDatasetDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := dataset.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid data in %s: %w", dir, err)
+	}
+	return NewSnapshot(dataset)
+}
+
+// datasetFingerprint describes names, sizes and modification times of the dataset files
+func datasetFingerprint(dir string) (string, error) {
+	var builder strings.Builder
+	for _, path := range DatasetFiles(dir) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		fmt.Fprintf(&builder, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"sw-config-api/deployments/db/migrations"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pressly/goose/v3"
+)
+
+// ErrSchemaOutdated is returned when the database schema is older than the code expects
+var ErrSchemaOutdated = errors.New("database schema is outdated")
+
+// Migrator applies SQL migrations embedded into the binary
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// NewMigrator creates a migrator for the database connection opened with the driver
+func NewMigrator(db *sqlx.DB, driver string) (*Migrator, error) {
+	var dialect goose.Dialect
+	var dir string
+	switch driver {
+	case DriverMySQL:
+		dialect, dir = goose.DialectMySQL, "."
+	case DriverPostgres:
+		dialect, dir = goose.DialectPostgres, "postgres"
+	default:
+		return nil, fmt.Errorf("migrations are not supported for storage driver: %s", driver)
+	}
+
+	fsys, err := fs.Sub(migrations.FS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s migrations: %w", driver, err)
+	}
+
+	provider, err := goose.NewProvider(dialect, db.DB, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration provider: %w", err)
+	}
+
+	return &Migrator{
+		provider: provider,
+	}, nil
+}
+
+// Up applies all pending migrations
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return results, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return results, nil
+}
+
+// Down rolls back the latest applied migration
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to roll back migration: %w", err)
+	}
+	return result, nil
+}
+
+// Status returns the state of all known migrations ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration status: %w", err)
+	}
+	return statuses, nil
+}
+
+// Versions returns the current schema version of the database and the version the code expects
+func (m *Migrator) Versions(ctx context.Context) (current, expected int64, err error) {
+	current, expected, err = m.provider.GetVersions(ctx)
+	if err != nil {
+		return current, expected, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return current, expected, nil
+}
+
+// CheckVersion returns ErrSchemaOutdated if the database schema is older than the code expects.
+// Newer schema versions are accepted to allow rolling back the service without rolling back migrations.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	current, expected, err := m.Versions(ctx)
+	if err != nil {
+		return err
+	}
+	if current < expected {
+		return fmt.Errorf("%w: version %d is older than required version %d", ErrSchemaOutdated, current, expected)
+	}
+	return nil
+}
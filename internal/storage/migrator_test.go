@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"io/fs"
+	"path"
+	"testing"
+
+	"sw-config-api/deployments/db/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMigrations_SameVersionsForAllDatabases(t *testing.T) {
+	mysql, err := fs.Glob(migrations.FS, "*.sql")
+	require.NoError(t, err)
+	postgres, err := fs.Glob(migrations.FS, "postgres/*.sql")
+	require.NoError(t, err)
+
+	require.NotEmpty(t, mysql)
+	require.Len(t, postgres, len(mysql))
+	for i := range mysql {
+		assert.Equal(t, path.Base(mysql[i]), path.Base(postgres[i]))
+	}
+}
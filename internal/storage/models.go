@@ -10,14 +10,21 @@ const (
 
 // Resource represents a generic resource in the database (asset, definition, etc.)
 type Resource struct {
-	Version string `db:"version"`
-	Hash    string `db:"hash"`
+	Version string `db:"version" json:"version"`
+	Hash    string `db:"hash" json:"hash"`
 }
 
 // PlatformVersion represents platform version information in the database
 type PlatformVersion struct {
-	RequiredVersion string `db:"required_version"`
-	StoreVersion    string `db:"store_version"`
+	RequiredVersion string `db:"required_version" json:"required_version"`
+	StoreVersion    string `db:"store_version" json:"store_version"`
+}
+
+// VersionLine is a MAJOR.MINOR line of app versions that has resources on a platform
+type VersionLine struct {
+	Platform string `db:"platform"`
+	Major    int64  `db:"major"`
+	Minor    int64  `db:"minor"`
 }
 
 // Entry point keys
@@ -21,10 +21,13 @@ func NewPlatformVersionRepository(ctx context.Context, db *sqlx.DB) (*PlatformVe
 }
 
 // GetPlatformVersion retrieves platform version information by platform
-func (r *PlatformVersionRepositoryImpl) GetPlatformVersion(ctx context.Context, platform string) (*PlatformVersion, error) {
+func (r *PlatformVersionRepositoryImpl) GetPlatformVersion(ctx context.Context, platform string) (_ *PlatformVersion, err error) {
+	ctx, q := startQuery(ctx, "PlatformVersionRepository.GetPlatformVersion", "platform_versions")
+	defer func() { q.end(err) }()
+
 	var platformVersion PlatformVersion
-	err := r.db.GetContext(ctx, &platformVersion,
-		"SELECT required_version, store_version FROM platform_versions WHERE platform = ?", platform)
+	err = r.db.GetContext(ctx, &platformVersion,
+		r.db.Rebind("SELECT required_version, store_version FROM platform_versions WHERE platform = ?"), platform)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, err // Return sql.ErrNoRows for "not found" case
@@ -33,3 +36,23 @@ func (r *PlatformVersionRepositoryImpl) GetPlatformVersion(ctx context.Context,
 	}
 	return &platformVersion, nil
 }
+
+// ListVersionLines lists distinct MAJOR.MINOR of assets and definitions on platforms that have a platform version
+func (r *PlatformVersionRepositoryImpl) ListVersionLines(ctx context.Context) (_ []VersionLine, err error) {
+	ctx, q := startQuery(ctx, "PlatformVersionRepository.ListVersionLines", "platform_versions")
+	defer func() { q.end(err) }()
+
+	var lines []VersionLine
+	err = r.db.SelectContext(ctx, &lines, `SELECT DISTINCT p.platform, r.major, r.minor
+		FROM platform_versions p
+		JOIN (
+			SELECT platform, major, minor FROM assets
+			UNION
+			SELECT platform, major, minor FROM definitions
+		) r ON r.platform = p.platform
+		ORDER BY p.platform, r.major DESC, r.minor DESC`)
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
@@ -23,7 +23,7 @@ type ResourceRepositoryImpl struct {
 func NewResourceRepository(ctx context.Context, db *sqlx.DB, tableName string, compatibility VersionCompatibility) (*ResourceRepositoryImpl, error) {
 	// Prepare statement for getting exact resource
 	getResourceStmt, err := db.PreparexContext(ctx,
-		fmt.Sprintf("SELECT version, hash FROM %s WHERE platform = ? AND version = ?", tableName))
+		db.Rebind(fmt.Sprintf("SELECT version, hash FROM %s WHERE platform = ? AND version = ?", tableName)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare getResource statement: %w", err)
 	}
@@ -33,16 +33,16 @@ func NewResourceRepository(ctx context.Context, db *sqlx.DB, tableName string, c
 	switch compatibility {
 	case MajorOnly:
 		getCompatibleResourceStmt, err = db.PreparexContext(ctx,
-			fmt.Sprintf(`SELECT version, hash FROM %s
+			db.Rebind(fmt.Sprintf(`SELECT version, hash FROM %s
 			 WHERE platform = ? AND major = ?
 			 ORDER BY major DESC, minor DESC, patch DESC
-			 LIMIT 1`, tableName))
+			 LIMIT 1`, tableName)))
 	case MajorMinor:
 		getCompatibleResourceStmt, err = db.PreparexContext(ctx,
-			fmt.Sprintf(`SELECT version, hash FROM %s
+			db.Rebind(fmt.Sprintf(`SELECT version, hash FROM %s
 			 WHERE platform = ? AND major = ? AND minor = ?
 			 ORDER BY major DESC, minor DESC, patch DESC
-			 LIMIT 1`, tableName))
+			 LIMIT 1`, tableName)))
 	default:
 		return nil, fmt.Errorf("unsupported compatibility level: %v", compatibility)
 	}
@@ -60,9 +60,12 @@ func NewResourceRepository(ctx context.Context, db *sqlx.DB, tableName string, c
 }
 
 // GetResource retrieves a resource by platform and version
-func (r *ResourceRepositoryImpl) GetResource(ctx context.Context, platform, version string) (*Resource, error) {
+func (r *ResourceRepositoryImpl) GetResource(ctx context.Context, platform, version string) (_ *Resource, err error) {
+	ctx, q := startQuery(ctx, "ResourceRepository.GetResource", r.tableName)
+	defer func() { q.end(err) }()
+
 	var resource Resource
-	err := r.getResourceStmt.GetContext(ctx, &resource, platform, version)
+	err = r.getResourceStmt.GetContext(ctx, &resource, platform, version)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, err // Return sql.ErrNoRows for "not found" case
@@ -73,7 +76,10 @@ func (r *ResourceRepositoryImpl) GetResource(ctx context.Context, platform, vers
 }
 
 // GetCompatibleResource retrieves a compatible resource by platform and app version
-func (r *ResourceRepositoryImpl) GetCompatibleResource(ctx context.Context, platform, appVersion string) (*Resource, error) {
+func (r *ResourceRepositoryImpl) GetCompatibleResource(ctx context.Context, platform, appVersion string) (_ *Resource, err error) {
+	ctx, q := startQuery(ctx, "ResourceRepository.GetCompatibleResource", r.tableName)
+	defer func() { q.end(err) }()
+
 	// Parse app version to get components
 	version, err := semver.NewVersion(appVersion)
 	if err != nil {
@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Masterminds/semver"
+)
+
+// Snapshot is an immutable in-memory index of a dataset
+// that resolves resources without database queries
+type Snapshot struct {
+	assets           resourceIndex
+	definitions      resourceIndex
+	assetURLs        []string
+	definitionURLs   []string
+	platformVersions map[string]PlatformVersion
+	entryPoints      map[string]string
+	createdAt        time.Time
+	dataset          *Dataset // Source of the index, compared with the next snapshot
+}
+
+// indexedResource is a resource with parsed version components
+type indexedResource struct {
+	Resource
+	major, minor, patch int64
+}
+
+// resourceIndex holds resources by platform sorted from the newest version to the oldest
+type resourceIndex map[string][]indexedResource
+
+// NewSnapshot builds a snapshot from the dataset
+func NewSnapshot(dataset *Dataset) (*Snapshot, error) {
+	assets, err := newResourceIndex(dataset.Assets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to index %s: %w", AssetsTable, err)
+	}
+
+	definitions, err := newResourceIndex(dataset.Definitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to index %s: %w", DefinitionsTable, err)
+	}
+
+	platformVersions := make(map[string]PlatformVersion, len(dataset.PlatformVersions))
+	for platform, platformVersion := range dataset.PlatformVersions {
+		platformVersions[platform] = platformVersion
+	}
+
+	entryPoints := make(map[string]string, len(dataset.EntryPoints))
+	for key, url := range dataset.EntryPoints {
+		entryPoints[key] = url
+	}
+
+	return &Snapshot{
+		assets:           assets,
+		definitions:      definitions,
+		assetURLs:        append([]string(nil), dataset.AssetURLs...),
+		definitionURLs:   append([]string(nil), dataset.DefinitionURLs...),
+		platformVersions: platformVersions,
+		entryPoints:      entryPoints,
+		createdAt:        time.Now(),
+		dataset:          dataset,
+	}, nil
+}
+
+// changesSince describes how the data has changed since the previous snapshot
+func (s *Snapshot) changesSince(previous *Snapshot) *DatasetDiff {
+	// Optional sections absent in the dataset are empty in the snapshot, not unchanged
+	current := *s.dataset
+	if current.PlatformVersions == nil {
+		current.PlatformVersions = map[string]PlatformVersion{}
+	}
+	if current.EntryPoints == nil {
+		current.EntryPoints = map[string]string{}
+	}
+	return DiffDatasets(previous.dataset, &current)
+}
+
+func newResourceIndex(resources map[string][]Resource) (resourceIndex, error) {
+	index := make(resourceIndex, len(resources))
+	for platform, list := range resources {
+		indexed := make([]indexedResource, 0, len(list))
+		for _, resource := range list {
+			major, minor, patch, err := versionComponents(resource.Version)
+			if err != nil {
+				return nil, err
+			}
+			indexed = append(indexed, indexedResource{
+				Resource: resource,
+				major:    major,
+				minor:    minor,
+				patch:    patch,
+			})
+		}
+
+		// Same ordering as ORDER BY major DESC, minor DESC, patch DESC
+		sort.SliceStable(indexed, func(i, j int) bool {
+			if indexed[i].major != indexed[j].major {
+				return indexed[i].major > indexed[j].major
+			}
+			if indexed[i].minor != indexed[j].minor {
+				return indexed[i].minor > indexed[j].minor
+			}
+			return indexed[i].patch > indexed[j].patch
+		})
+		index[platform] = indexed
+	}
+	return index, nil
+}
+
+// CreatedAt returns the time when the snapshot was built
+func (s *Snapshot) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+// resources returns the index of the given resource table
+func (s *Snapshot) resources(tableName string) resourceIndex {
+	switch tableName {
+	case AssetsTable:
+		return s.assets
+	case DefinitionsTable:
+		return s.definitions
+	default:
+		return nil
+	}
+}
+
+// urls returns URLs of the given URL table
+func (s *Snapshot) urls(tableName string) []string {
+	switch tableName {
+	case AssetURLsTable:
+		return s.assetURLs
+	case DefinitionURLsTable:
+		return s.definitionURLs
+	default:
+		return nil
+	}
+}
+
+// versionLines returns the same lines as the database query, sorted by platform and newest line first
+func (s *Snapshot) versionLines() []VersionLine {
+	seen := make(map[VersionLine]struct{})
+	var lines []VersionLine
+	for _, index := range []resourceIndex{s.assets, s.definitions} {
+		for platform, resources := range index {
+			if _, ok := s.platformVersions[platform]; !ok {
+				continue
+			}
+			for _, resource := range resources {
+				line := VersionLine{Platform: platform, Major: resource.major, Minor: resource.minor}
+				if _, ok := seen[line]; !ok {
+					seen[line] = struct{}{}
+					lines = append(lines, line)
+				}
+			}
+		}
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Platform != lines[j].Platform {
+			return lines[i].Platform < lines[j].Platform
+		}
+		if lines[i].Major != lines[j].Major {
+			return lines[i].Major > lines[j].Major
+		}
+		return lines[i].Minor > lines[j].Minor
+	})
+	return lines
+}
+
+// getResource finds a resource by platform and exact version
+func (index resourceIndex) getResource(platform, version string) (*Resource, error) {
+	for _, resource := range index[platform] {
+		if resource.Version == version {
+			result := resource.Resource
+			return &result, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// getCompatibleResource finds the newest resource compatible with the app version
+func (index resourceIndex) getCompatibleResource(platform, appVersion string, compatibility VersionCompatibility) (*Resource, error) {
+	version, err := semver.NewVersion(appVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse app version %s: %w", appVersion, err)
+	}
+
+	for _, resource := range index[platform] {
+		switch compatibility {
+		case MajorOnly:
+			if resource.major != version.Major() {
+				continue
+			}
+		case MajorMinor:
+			if resource.major != version.Major() || resource.minor != version.Minor() {
+				continue
+			}
+		default:
+			return nil, fmt.Errorf("unsupported compatibility level: %v", compatibility)
+		}
+		result := resource.Resource
+		return &result, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+// SnapshotStore holds the current snapshot and allows to replace it atomically
+type SnapshotStore struct {
+	current atomic.Pointer[Snapshot]
+
+	mu       sync.Mutex // Serializes replacements and change notifications
+	onChange func(ctx context.Context, diff *DatasetDiff)
+}
+
+// NewSnapshotStore creates a store with the initial snapshot
+func NewSnapshotStore(snapshot *Snapshot) *SnapshotStore {
+	store := &SnapshotStore{}
+	store.Store(snapshot)
+	return store
+}
+
+// Load returns the current snapshot
+func (s *SnapshotStore) Load() *Snapshot {
+	return s.current.Load()
+}
+
+// Store replaces the current snapshot
+func (s *SnapshotStore) Store(snapshot *Snapshot) {
+	s.current.Store(snapshot)
+}
+
+// OnChange sets a function called by Replace when the data of the new snapshot differs,
+// e.g. to drop configurations cached from the previous one
+func (s *SnapshotStore) OnChange(fn func(ctx context.Context, diff *DatasetDiff)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = fn
+}
+
+// Replace replaces the current snapshot and reports changes of the data to the OnChange function.
+// The function is called after the new snapshot is visible, so that dropped entries are not
+// cached again from the previous one.
+func (s *SnapshotStore) Replace(ctx context.Context, snapshot *Snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.current.Swap(snapshot)
+	if s.onChange == nil || previous == nil {
+		return
+	}
+	if diff := snapshot.changesSince(previous); !diff.IsEmpty() {
+		s.onChange(ctx, diff)
+	}
+}
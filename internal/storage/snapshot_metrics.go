@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterSnapshotMetrics reports the age of the current snapshot in the store
+func RegisterSnapshotMetrics(meter metric.Meter, store *SnapshotStore) (metric.Registration, error) {
+	age, err := meter.Float64ObservableGauge(
+		"storage.snapshot.age",
+		metric.WithDescription("Time since the in-memory configuration snapshot was built"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		observer.ObserveFloat64(age, time.Since(store.Load().CreatedAt()).Seconds())
+		return nil
+	}, age)
+}
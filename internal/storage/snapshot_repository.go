@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SnapshotResourceRepository implements service.ResourceRepo on top of a snapshot
+type SnapshotResourceRepository struct {
+	store         *SnapshotStore
+	tableName     string
+	compatibility VersionCompatibility
+}
+
+// NewSnapshotResourceRepository creates a new snapshot resource repository
+func NewSnapshotResourceRepository(store *SnapshotStore, tableName string, compatibility VersionCompatibility) (*SnapshotResourceRepository, error) {
+	if tableName != AssetsTable && tableName != DefinitionsTable {
+		return nil, fmt.Errorf("unsupported resource table: %s", tableName)
+	}
+	if compatibility != MajorOnly && compatibility != MajorMinor {
+		return nil, fmt.Errorf("unsupported compatibility level: %v", compatibility)
+	}
+
+	return &SnapshotResourceRepository{
+		store:         store,
+		tableName:     tableName,
+		compatibility: compatibility,
+	}, nil
+}
+
+// GetResource retrieves a resource by platform and version
+func (r *SnapshotResourceRepository) GetResource(ctx context.Context, platform, version string) (*Resource, error) {
+	return r.store.Load().resources(r.tableName).getResource(platform, version)
+}
+
+// GetCompatibleResource retrieves a compatible resource by platform and app version
+func (r *SnapshotResourceRepository) GetCompatibleResource(ctx context.Context, platform, appVersion string) (*Resource, error) {
+	return r.store.Load().resources(r.tableName).getCompatibleResource(platform, appVersion, r.compatibility)
+}
+
+// SnapshotURLRepository implements service.URLRepo on top of a snapshot
+type SnapshotURLRepository struct {
+	store     *SnapshotStore
+	tableName string
+}
+
+// NewSnapshotURLRepository creates a new snapshot URL repository
+func NewSnapshotURLRepository(store *SnapshotStore, tableName string) (*SnapshotURLRepository, error) {
+	if tableName != AssetURLsTable && tableName != DefinitionURLsTable {
+		return nil, fmt.Errorf("unsupported URL table: %s", tableName)
+	}
+
+	return &SnapshotURLRepository{
+		store:     store,
+		tableName: tableName,
+	}, nil
+}
+
+// ListURLs retrieves all URLs
+func (r *SnapshotURLRepository) ListURLs(ctx context.Context) ([]string, error) {
+	urls := r.store.Load().urls(r.tableName)
+	return append(make([]string, 0, len(urls)), urls...), nil
+}
+
+// SnapshotPlatformVersionRepository implements service.PlatformVersionRepository on top of a snapshot
+type SnapshotPlatformVersionRepository struct {
+	store *SnapshotStore
+}
+
+// NewSnapshotPlatformVersionRepository creates a new snapshot platform version repository
+func NewSnapshotPlatformVersionRepository(store *SnapshotStore) *SnapshotPlatformVersionRepository {
+	return &SnapshotPlatformVersionRepository{
+		store: store,
+	}
+}
+
+// GetPlatformVersion retrieves platform version information by platform
+func (r *SnapshotPlatformVersionRepository) GetPlatformVersion(ctx context.Context, platform string) (*PlatformVersion, error) {
+	platformVersion, ok := r.store.Load().platformVersions[platform]
+	if !ok {
+		return nil, sql.ErrNoRows // Same "not found" error as the database repository
+	}
+	return &platformVersion, nil
+}
+
+// ListVersionLines lists distinct MAJOR.MINOR of assets and definitions on platforms that have a platform version
+func (r *SnapshotPlatformVersionRepository) ListVersionLines(ctx context.Context) ([]VersionLine, error) {
+	return r.store.Load().versionLines(), nil
+}
+
+// SnapshotEntryPointRepository implements service.EntryPointRepository on top of a snapshot
+type SnapshotEntryPointRepository struct {
+	store *SnapshotStore
+}
+
+// NewSnapshotEntryPointRepository creates a new snapshot entry point repository
+func NewSnapshotEntryPointRepository(store *SnapshotStore) *SnapshotEntryPointRepository {
+	return &SnapshotEntryPointRepository{
+		store: store,
+	}
+}
+
+// Get retrieves all entry points as a map of key to URL
+func (r *SnapshotEntryPointRepository) Get(ctx context.Context) (map[string]string, error) {
+	entryPoints := r.store.Load().entryPoints
+	result := make(map[string]string, len(entryPoints))
+	for key, url := range entryPoints {
+		result[key] = url
+	}
+	return result, nil
+}
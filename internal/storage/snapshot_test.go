@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshotStore(t *testing.T) *SnapshotStore {
+	t.Helper()
+
+	dataset, err := ReadDatasetDir("../../deployments/db/migrations/data")
+	require.NoError(t, err)
+
+	snapshot, err := NewSnapshot(dataset)
+	require.NoError(t, err)
+
+	return NewSnapshotStore(snapshot)
+}
+
+func TestSnapshotResourceRepository_GetCompatibleResource(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSnapshotStore(t)
+
+	assets, err := NewSnapshotResourceRepository(store, AssetsTable, MajorOnly)
+	require.NoError(t, err)
+	definitions, err := NewSnapshotResourceRepository(store, DefinitionsTable, MajorMinor)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		repository *SnapshotResourceRepository
+		platform   string
+		appVersion string
+		expected   string
+	}{
+		{"assets_major_only_latest", assets, "android", "14.0.0", "14.8.447"},
+		{"assets_major_only_older", assets, "android", "13.0.0", "13.9.519"},
+		{"definitions_major_minor", definitions, "android", "14.8.447", "14.8.98"},
+		{"definitions_other_platform", definitions, "ios", "13.5.0", "13.5.693"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resource, err := tc.repository.GetCompatibleResource(ctx, tc.platform, tc.appVersion)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, resource.Version)
+			assert.NotEmpty(t, resource.Hash)
+		})
+	}
+
+	_, err = assets.GetCompatibleResource(ctx, "android", "20.0.0")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = definitions.GetCompatibleResource(ctx, "android", "14.2.0")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = assets.GetCompatibleResource(ctx, "unknown", "14.0.0")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSnapshotRepositories_Lookups(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSnapshotStore(t)
+
+	assets, err := NewSnapshotResourceRepository(store, AssetsTable, MajorOnly)
+	require.NoError(t, err)
+
+	resource, err := assets.GetResource(ctx, "ios", "12.4.328")
+	require.NoError(t, err)
+	assert.Equal(t, "12.4.328", resource.Version)
+
+	_, err = assets.GetResource(ctx, "android", "12.4.328")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	platformVersion, err := NewSnapshotPlatformVersionRepository(store).GetPlatformVersion(ctx, "android")
+	require.NoError(t, err)
+	assert.Equal(t, "13.7.556", platformVersion.StoreVersion)
+
+	_, err = NewSnapshotPlatformVersionRepository(store).GetPlatformVersion(ctx, "unknown")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	entryPoints, err := NewSnapshotEntryPointRepository(store).Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "api.application.com/jsonrpc/v2", entryPoints[BackendEntryPointKey])
+
+	urlRepository, err := NewSnapshotURLRepository(store, DefinitionURLsTable)
+	require.NoError(t, err)
+	urls, err := urlRepository.ListURLs(ctx)
+	require.NoError(t, err)
+	assert.Len(t, urls, 5)
+}
+
+func TestFileStore_ReloadsYAMLFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	writeFile("assets.yaml", "android:\n  - version: 14.8.447\n    hash: "+testHashA+"\n")
+	writeFile("definitions.yaml", "android:\n  - version: 14.8.98\n    hash: "+testHashB+"\n")
+	writeFile("assets_urls.yaml", "assets_urls: [a.cdn.application.com]\n")
+	writeFile("definitions_urls.yaml", "definitions_urls: [d.cdn.application.com]\n")
+
+	store, err := NewFileStore(dir, 10*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	defer store.Close() // nolint:errcheck
+
+	var (
+		mu    sync.Mutex
+		diffs []*DatasetDiff
+	)
+	store.OnChange(func(_ context.Context, diff *DatasetDiff) {
+		mu.Lock()
+		defer mu.Unlock()
+		diffs = append(diffs, diff)
+	})
+	changes := func() []*DatasetDiff {
+		mu.Lock()
+		defer mu.Unlock()
+		return diffs
+	}
+
+	assets, err := NewSnapshotResourceRepository(store.SnapshotStore, AssetsTable, MajorOnly)
+	require.NoError(t, err)
+
+	resource, err := assets.GetCompatibleResource(context.Background(), "android", "14.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "14.8.447", resource.Version)
+
+	// Invalid data is ignored and the previous snapshot is kept
+	writeFile("assets.yaml", "android:\n  - version: invalid\n    hash: "+testHashA+"\n")
+	time.Sleep(50 * time.Millisecond)
+	resource, err = assets.GetCompatibleResource(context.Background(), "android", "14.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "14.8.447", resource.Version)
+	assert.Empty(t, changes())
+
+	writeFile("assets.yaml", "android:\n  - version: 14.9.1\n    hash: "+testHashA+"\n")
+	assert.Eventually(t, func() bool {
+		resource, err := assets.GetCompatibleResource(context.Background(), "android", "14.0.0")
+		return err == nil && resource.Version == "14.9.1"
+	}, time.Second, 10*time.Millisecond)
+
+	// Changes are reported once the new snapshot is visible. A partially written file
+	// may be loaded first, so only the last change is checked.
+	added := []PlatformResource{{Platform: "android", Version: "14.9.1", Hash: testHashA}}
+	var last *DatasetDiff
+	require.Eventually(t, func() bool {
+		reported := changes()
+		if len(reported) == 0 {
+			return false
+		}
+		last = reported[len(reported)-1]
+		return assert.ObjectsAreEqual(added, last.Assets.Added)
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, last.Definitions.IsEmpty())
+	assert.True(t, last.PlatformVersions.IsEmpty())
+}
+
+func TestSnapshotStore_ReplaceReportsChanges(t *testing.T) {
+	newSnapshot := func(dataset *Dataset) *Snapshot {
+		snapshot, err := NewSnapshot(dataset)
+		require.NoError(t, err)
+		return snapshot
+	}
+	dataset := &Dataset{
+		Assets:      map[string][]Resource{"android": {{Version: "14.8.447", Hash: testHashA}}},
+		AssetURLs:   []string{"a.cdn.application.com"},
+		EntryPoints: map[string]string{"backend": "https://api.application.com"},
+	}
+	store := NewSnapshotStore(newSnapshot(dataset))
+
+	var diffs []*DatasetDiff
+	store.OnChange(func(_ context.Context, diff *DatasetDiff) { diffs = append(diffs, diff) })
+
+	// The same data is not reported
+	store.Replace(context.Background(), newSnapshot(dataset))
+	assert.Empty(t, diffs)
+
+	// A missing optional section empties the snapshot, so its entries are removed
+	changed := *dataset
+	changed.AssetURLs = []string{"b.cdn.application.com"}
+	changed.EntryPoints = nil
+	snapshot := newSnapshot(&changed)
+	store.Replace(context.Background(), snapshot)
+
+	require.Len(t, diffs, 1)
+	assert.Same(t, snapshot, store.Load())
+	assert.True(t, diffs[0].Assets.IsEmpty())
+	assert.Equal(t, URLDiff{Added: []string{"b.cdn.application.com"}, Removed: []string{"a.cdn.application.com"}}, diffs[0].AssetURLs)
+	assert.Equal(t, []EntryPointValue{{Key: "backend", URL: "https://api.application.com"}}, diffs[0].EntryPoints.Removed)
+}
+
+func TestSnapshotPlatformVersionRepository_ListVersionLines(t *testing.T) {
+	dataset := &Dataset{
+		Assets: map[string][]Resource{
+			"android": {{Version: "14.8.447", Hash: testHashA}, {Version: "14.8.1", Hash: testHashA}, {Version: "13.9.519", Hash: testHashA}},
+			"web":     {{Version: "1.0.0", Hash: testHashA}},
+		},
+		Definitions: map[string][]Resource{
+			"android": {{Version: "14.8.98", Hash: testHashB}, {Version: "14.7.10", Hash: testHashB}},
+		},
+		PlatformVersions: map[string]PlatformVersion{
+			"android": {RequiredVersion: "13.0.0", StoreVersion: "14.8.447"},
+		},
+	}
+	snapshot, err := NewSnapshot(dataset)
+	require.NoError(t, err)
+
+	lines, err := NewSnapshotPlatformVersionRepository(NewSnapshotStore(snapshot)).ListVersionLines(context.Background())
+	require.NoError(t, err)
+
+	// Platforms without a platform version are skipped
+	assert.Equal(t, []VersionLine{
+		{Platform: "android", Major: 14, Minor: 8},
+		{Platform: "android", Major: 14, Minor: 7},
+		{Platform: "android", Major: 13, Minor: 9},
+	}, lines)
+}
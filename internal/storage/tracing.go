@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"sw-config-api/internal/logging"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates spans of repository calls with the global tracer provider
+var tracer = otel.Tracer("sw-config-api/storage")
+
+// query traces and logs a repository call
+type query struct {
+	ctx   context.Context
+	span  trace.Span
+	name  string
+	table string
+	start time.Time
+}
+
+// startQuery starts a client span of a query to the table
+func startQuery(ctx context.Context, name, table string) (context.Context, *query) {
+	ctx, span := tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.collection.name", table)),
+	)
+	return ctx, &query{ctx: ctx, span: span, name: name, table: table, start: time.Now()}
+}
+
+// end ends the span recording the error and logs the query at DEBUG level with the
+// request logger. Missing rows are an expected result, not an error.
+func (q *query) end(err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		q.span.RecordError(err)
+		q.span.SetStatus(codes.Error, err.Error())
+	}
+	q.span.End()
+
+	logger := logging.FromContext(q.ctx, slog.Default())
+	if !logger.Enabled(q.ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []any{"query", q.name, "table", q.table, "duration_ms", time.Since(q.start).Milliseconds()}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+	logger.DebugContext(q.ctx, "query completed", attrs...)
+}
@@ -31,11 +31,14 @@ func NewURLRepository(ctx context.Context, db *sqlx.DB, tableName string) (*URLR
 }
 
 // ListURLs retrieves all URLs
-func (r *URLRepositoryImpl) ListURLs(ctx context.Context) ([]string, error) {
+func (r *URLRepositoryImpl) ListURLs(ctx context.Context) (_ []string, err error) {
+	ctx, q := startQuery(ctx, "URLRepository.ListURLs", r.tableName)
+	defer func() { q.end(err) }()
+
 	var urls []struct {
 		URL string `db:"url"`
 	}
-	err := r.listURLsStmt.SelectContext(ctx, &urls)
+	err = r.listURLsStmt.SelectContext(ctx, &urls)
 	if err != nil {
 		return nil, err
 	}